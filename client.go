@@ -0,0 +1,65 @@
+package gorinth
+
+import (
+	"net/http"
+	"sync"
+)
+
+// A client used to communicate with the Modrinth API.
+// The zero value is ready to use, and behaves the same as DefaultClient
+type Client struct {
+	// The API that requests are sent to. Defaults to EnvironmentProduction
+	Environment Environment
+	// The HTTP client used to send requests. Defaults to http.DefaultClient
+	HTTPClient *http.Client
+	// The auth token used for requests which aren't given one explicitly
+	Auth string
+	// Identifies the application to Modrinth. Should always be set
+	UserAgent UserAgent
+	// Headers which are sent with every request
+	Headers map[string]string
+	// How requests are retried after transient failures. Defaults to DefaultRetryPolicy
+	RetryPolicy *RetryPolicy
+	// Receives warnings, retry notices and request traces. Defaults to discarding everything
+	Logger Logger
+
+	limiter          rateLimiter
+	userAgentWarning sync.Once
+}
+
+// The client used by the package-level functions
+var DefaultClient = NewClient()
+
+// Creates a client for the production Modrinth API
+func NewClient() *Client {
+	return &Client{
+		Environment: EnvironmentProduction,
+		HTTPClient:  &http.Client{},
+		Headers:     map[string]string{},
+	}
+}
+
+// Returns the client, or DefaultClient if it is nil
+func (c *Client) orDefault() *Client {
+	if c == nil {
+		return DefaultClient
+	}
+	return c
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return http.DefaultClient
+	}
+	return c.HTTPClient
+}
+
+func (c *Client) authHeader(auth string) map[string]string {
+	if auth == "" {
+		auth = c.Auth
+	}
+	if auth == "" {
+		return map[string]string{}
+	}
+	return map[string]string{"Authorization": auth}
+}
@@ -1,16 +1,102 @@
 package gorinth
 
+import (
+	"context"
+	"encoding/json"
+)
+
 // Gets the version associated with a dependency
 func (dep *Dependency) GetVersion() (*Version, error) {
+	return dep.GetVersionContext(context.Background())
+}
+
+// Gets the version associated with a dependency, cancelling the request if the context is done
+func (dep *Dependency) GetVersionContext(ctx context.Context) (*Version, error) {
+	c := dep.client.orDefault()
 	if dep.VersionId == nil {
-		project, err := GetProject(*dep.ProjectId, "")
+		project, err := c.GetProjectContext(ctx, *dep.ProjectId, "")
 
 		if err != nil {
 			return nil, err
 		}
 
-		return project.GetLatestVersion()
+		return project.GetLatestVersionContext(ctx)
+	}
+
+	return c.GetVersionContext(ctx, *dep.VersionId, "")
+}
+
+// Returns every project and version that any version of the project depends on, in one request.
+// Call Link on the result to find which versions of the project depend on them
+func (project *Project) GetDependencies() (*ProjectDependencies, error) {
+	return project.GetDependenciesContext(context.Background())
+}
+
+// Returns every project and version that any version of the project depends on, in one request,
+// cancelling the request if the context is done.
+// Call Link on the result to find which versions of the project depend on them
+func (project *Project) GetDependenciesContext(ctx context.Context) (*ProjectDependencies, error) {
+	c := project.client.orDefault()
+	response, err := c.get(ctx, c.endpoint("project", project.Id, "dependencies"), c.authHeader(project.auth))
+	if err != nil {
+		return nil, err
+	}
+
+	if response.status == 404 {
+		return nil, response.error("Project %q wasn't found or no authorization to see this project", project.Slug)
+	}
+
+	if response.status != 200 {
+		return nil, response.error("Unexpected status code when fetching dependencies of project %q", project.Slug)
+	}
+
+	dependencies := &ProjectDependencies{}
+	err = json.Unmarshal(response.body, &dependencies)
+	if err != nil {
+		return nil, err
 	}
 
-	return GetVersion(*dep.VersionId, "")
+	for i := range dependencies.Projects {
+		dependencies.Projects[i].auth = project.auth
+		dependencies.Projects[i].client = c
+	}
+	for i := range dependencies.Versions {
+		dependencies.Versions[i].attach(c)
+	}
+
+	return dependencies, nil
+}
+
+// Links each dependency of the given versions to the project and version it refers to, replacing Links.
+// Pass the versions returned by the project's GetVersions to link every dependency of the project
+func (dependencies *ProjectDependencies) Link(dependents []Version) {
+	projects := map[string]*Project{}
+	for i := range dependencies.Projects {
+		projects[dependencies.Projects[i].Id] = &dependencies.Projects[i]
+	}
+	versions := map[string]*Version{}
+	for i := range dependencies.Versions {
+		versions[dependencies.Versions[i].Id] = &dependencies.Versions[i]
+	}
+
+	dependencies.Links = []DependencyLink{}
+	for i := range dependents {
+		dependent := &dependents[i]
+
+		for _, dependency := range dependent.Dependencies {
+			link := DependencyLink{Dependent: dependent, Dependency: dependency}
+
+			if dependency.VersionId != nil {
+				link.Version = versions[*dependency.VersionId]
+			}
+
+			if dependency.ProjectId != nil {
+				link.Project = projects[*dependency.ProjectId]
+			} else if link.Version != nil {
+				link.Project = projects[link.Version.ProjectId]
+			}
+
+			dependencies.Links = append(dependencies.Links, link)
+		}
+	}
 }
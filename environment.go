@@ -0,0 +1,38 @@
+package gorinth
+
+import (
+	"net/url"
+	"strings"
+)
+
+// The Modrinth API a client sends requests to, identified by its base URL
+type Environment string
+
+const (
+	// The production Modrinth API, used by default
+	EnvironmentProduction Environment = "https://api.modrinth.com/v2"
+	// The staging Modrinth API, for testing against a separate set of data
+	EnvironmentStaging Environment = "https://staging-api.modrinth.com/v2"
+)
+
+// Creates an environment for a custom base URL, such as a local fake of the API
+func CustomEnvironment(baseURL string) Environment {
+	return Environment(strings.TrimSuffix(baseURL, "/"))
+}
+
+func (c *Client) environment() Environment {
+	if c.Environment == "" {
+		return EnvironmentProduction
+	}
+	return c.Environment
+}
+
+// Builds the URL of an API endpoint from its path segments, escaping each one
+func (c *Client) endpoint(segments ...string) string {
+	path := make([]string, len(segments))
+	for i, segment := range segments {
+		path[i] = url.PathEscape(segment)
+	}
+
+	return string(c.environment()) + "/" + strings.Join(path, "/")
+}
@@ -1,7 +1,9 @@
 package gorinth
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
 )
 
 func format(log string, logType string, values ...any) string {
@@ -18,8 +20,116 @@ func makeError(message string, values ...any) gorinthError {
 	return gorinthError(fmt.Sprintf(message, values...))
 }
 
-func logWarning(warn string, values ...any) {
-	fmt.Println(format(warn, "Warning", values...))
+// An error found by checking values before sending a request, which matches ErrInvalidInput,
+// along with a more specific sentinel error if one is given
+type validationError struct {
+	gorinthError
+	kind error
+}
+
+func (err validationError) Is(target error) bool {
+	return target == ErrInvalidInput || (err.kind != nil && target == err.kind)
+}
+
+func makeValidationError(message string, values ...any) error {
+	return validationError{gorinthError: makeError(message, values...)}
+}
+
+// An error which matches a sentinel error with errors.Is, while keeping a descriptive message
+type kindError struct {
+	gorinthError
+	kind error
+}
+
+func (err kindError) Is(target error) bool {
+	return target == err.kind
+}
+
+// Sentinel errors which an *APIError matches with errors.Is, depending on its status code
+var (
+	// The resource doesn't exist, or the auth token has no permission to see it (404)
+	ErrNotFound error = gorinthError("Not found")
+	// The auth token is missing, invalid or lacks the required scopes (401 or 403)
+	ErrUnauthorized error = gorinthError("Unauthorized")
+	// Too many requests have been sent in a short period of time (429)
+	ErrRateLimited error = gorinthError("Rate limited")
+	// The request was malformed or contained invalid values (400)
+	ErrInvalidInput error = gorinthError("Invalid input")
+)
+
+// Returned when a destructive operation is refused by a safety check,
+// such as deleting a project which has been downloaded. Pass Force to skip the check
+var ErrSafetyCheck error = gorinthError("Refused by safety check")
+
+// Returned when creating a project whose slug is already used by another project,
+// if the slug is checked first. Also matches ErrInvalidInput
+var ErrSlugTaken error = gorinthError("Slug already taken")
+
+// An error response returned by the Modrinth API
+type APIError struct {
+	// The HTTP status code of the response
+	StatusCode int
+	// The kind of error reported by Modrinth, such as "not_found" or "invalid_input"
+	Kind string
+	// The description of the error reported by Modrinth
+	Description string
+	// The raw body of the response
+	Body []byte
+	// The method and URL of the request, such as "GET https://api.modrinth.com/v2/project/foo"
+	Endpoint string
+	// What was being attempted when the error occurred
+	message string
+}
+
+func newAPIError(endpoint string, status int, body []byte, message string, values ...any) *APIError {
+	responseSchema := struct {
+		Error       string `json:"error"`
+		Description string `json:"description"`
+	}{}
+
+	// The body isn't always JSON, in which case only the raw body is available
+	json.Unmarshal(body, &responseSchema)
+
+	return &APIError{
+		StatusCode:  status,
+		Kind:        responseSchema.Error,
+		Description: responseSchema.Description,
+		Body:        body,
+		Endpoint:    endpoint,
+		message:     fmt.Sprintf(message, values...),
+	}
+}
+
+func (err *APIError) Error() string {
+	message := err.message
+	if message == "" {
+		message = "Unexpected response"
+	}
+
+	details := fmt.Sprintf("status %d from %s", err.StatusCode, err.Endpoint)
+	if err.Kind != "" {
+		details += ", " + err.Kind
+	}
+	if err.Description != "" {
+		details += ": " + err.Description
+	}
+
+	return format("%s (%s)", "Error", message, details)
+}
+
+// Reports whether the error matches one of the sentinel errors, based on its status code
+func (err *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return err.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return err.StatusCode == http.StatusUnauthorized || err.StatusCode == http.StatusForbidden
+	case ErrRateLimited:
+		return err.StatusCode == http.StatusTooManyRequests
+	case ErrInvalidInput:
+		return err.StatusCode == http.StatusBadRequest
+	}
+	return false
 }
 
 // func logInfo(info string, values ...any) {
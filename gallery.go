@@ -0,0 +1,172 @@
+package gorinth
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// The file extensions of image types accepted by Modrinth, keyed by MIME type
+var imageExtensions = map[string]string{
+	"image/png":  "png",
+	"image/jpeg": "jpg",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+	"image/bmp":  "bmp",
+}
+
+// Detects the type of an image from its contents, returning its file extension
+func imageExtension(image []byte) (string, bool) {
+	if ext, ok := imageExtensions[http.DetectContentType(image)]; ok {
+		return ext, true
+	}
+
+	// SVGs are XML, so aren't recognised by content sniffing
+	trimmed := bytes.TrimSpace(image)
+	if bytes.HasPrefix(trimmed, []byte("<svg")) ||
+		(bytes.HasPrefix(trimmed, []byte("<?xml")) && bytes.Contains(trimmed, []byte("<svg"))) {
+		return "svg", true
+	}
+
+	return "", false
+}
+
+// Adds an image to the project's gallery, then updates the project's Gallery field
+func (project *Project) AddGalleryImage(image NewGalleryImage, auth string) error {
+	return project.AddGalleryImageContext(context.Background(), image, auth)
+}
+
+// Adds an image to the project's gallery, then updates the project's Gallery field,
+// cancelling the requests if the context is done
+func (project *Project) AddGalleryImageContext(ctx context.Context, image NewGalleryImage, auth string) error {
+	ext, ok := imageExtension(image.Image)
+	if !ok {
+		return makeValidationError("Unsupported gallery image type for project %q, must be PNG, JPEG, GIF, WebP, BMP or SVG", project.Slug)
+	}
+
+	query := url.Values{}
+	query.Set("ext", ext)
+	query.Set("featured", strconv.FormatBool(image.Featured))
+	if image.Title != nil {
+		query.Set("title", *image.Title)
+	}
+	if image.Description != nil {
+		query.Set("description", *image.Description)
+	}
+	if image.Ordering != nil {
+		query.Set("ordering", strconv.Itoa(*image.Ordering))
+	}
+
+	c := project.client.orDefault()
+	url := c.endpoint("project", project.Id, "gallery") + "?" + query.Encode()
+	response, err := c.sendRaw(ctx, http.MethodPost, url, image.Image, imageContentType(ext), c.authHeader(auth))
+	if err != nil {
+		return err
+	}
+
+	if response.status != 204 {
+		return galleryError(response, "add an image to", project)
+	}
+
+	return project.refreshGallery(ctx, auth)
+}
+
+// Changes the image in the project's gallery with the given URL,
+// then updates the project's Gallery field
+func (project *Project) ModifyGalleryImage(imageUrl string, changes GalleryImageChanges, auth string) error {
+	return project.ModifyGalleryImageContext(context.Background(), imageUrl, changes, auth)
+}
+
+// Changes the image in the project's gallery with the given URL,
+// then updates the project's Gallery field, cancelling the requests if the context is done
+func (project *Project) ModifyGalleryImageContext(ctx context.Context, imageUrl string, changes GalleryImageChanges, auth string) error {
+	query := url.Values{}
+	query.Set("url", imageUrl)
+	if changes.Featured != nil {
+		query.Set("featured", strconv.FormatBool(*changes.Featured))
+	}
+	if changes.Title != nil {
+		query.Set("title", *changes.Title)
+	}
+	if changes.Description != nil {
+		query.Set("description", *changes.Description)
+	}
+	if changes.Ordering != nil {
+		query.Set("ordering", strconv.Itoa(*changes.Ordering))
+	}
+
+	c := project.client.orDefault()
+	url := c.endpoint("project", project.Id, "gallery") + "?" + query.Encode()
+	response, err := c.patch(ctx, url, nil, c.authHeader(auth))
+	if err != nil {
+		return err
+	}
+
+	if response.status != 204 {
+		return galleryError(response, "modify an image in", project)
+	}
+
+	return project.refreshGallery(ctx, auth)
+}
+
+// Removes the image with the given URL from the project's gallery,
+// then updates the project's Gallery field
+func (project *Project) DeleteGalleryImage(imageUrl string, auth string) error {
+	return project.DeleteGalleryImageContext(context.Background(), imageUrl, auth)
+}
+
+// Removes the image with the given URL from the project's gallery,
+// then updates the project's Gallery field, cancelling the requests if the context is done
+func (project *Project) DeleteGalleryImageContext(ctx context.Context, imageUrl string, auth string) error {
+	c := project.client.orDefault()
+	url := c.endpoint("project", project.Id, "gallery") + "?url=" + url.QueryEscape(imageUrl)
+	response, err := c.delete(ctx, url, c.authHeader(auth))
+	if err != nil {
+		return err
+	}
+
+	if response.status != 204 {
+		return galleryError(response, "delete an image from", project)
+	}
+
+	return project.refreshGallery(ctx, auth)
+}
+
+func galleryError(response *response, action string, project *Project) *APIError {
+	switch response.status {
+	case 400:
+		return response.error("Invalid request when attempting to %s the gallery of project %q", action, project.Slug)
+	case 401:
+		return response.error("No authorisation to %s the gallery of project %q", action, project.Slug)
+	case 404:
+		return response.error("Project %q wasn't found or no authorization to see this project", project.Slug)
+	}
+	return response.error("Unexpected status code when attempting to %s the gallery of project %q", action, project.Slug)
+}
+
+// Fetches the project's gallery again, after it has been changed
+func (project *Project) refreshGallery(ctx context.Context, auth string) error {
+	current, err := project.client.orDefault().GetProjectContext(ctx, project.Id, auth)
+	if err != nil {
+		return err
+	}
+
+	project.Gallery = current.Gallery
+	return nil
+}
+
+// Returns the MIME type of an image file extension
+func imageContentType(ext string) string {
+	if ext == "svg" {
+		return "image/svg+xml"
+	}
+	for contentType, extension := range imageExtensions {
+		if extension == strings.ToLower(ext) {
+			return contentType
+		}
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,33 @@
+package gorinth
+
+import "testing"
+
+func TestImageExtension(t *testing.T) {
+	tests := []struct {
+		name        string
+		image       string
+		ext         string
+		contentType string
+	}{
+		{"PNG", "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR", "png", "image/png"},
+		{"JPEG", "\xff\xd8\xff\xe0\x00\x10JFIF\x00", "jpg", "image/jpeg"},
+		{"GIF", "GIF89a\x01\x00\x01\x00", "gif", "image/gif"},
+		{"WebP", "RIFF\x24\x00\x00\x00WEBPVP8 ", "webp", "image/webp"},
+		{"SVG", `<svg xmlns="http://www.w3.org/2000/svg"></svg>`, "svg", "image/svg+xml"},
+		{"SVG with XML declaration", "  <?xml version=\"1.0\"?>\n<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>", "svg", "image/svg+xml"},
+		{"XML which isn't an SVG", `<?xml version="1.0"?><project></project>`, "", ""},
+		{"garbage", "\x00\x01\x02 not an image", "", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ext, ok := imageExtension([]byte(test.image))
+			if ext != test.ext || ok != (test.ext != "") {
+				t.Fatalf("imageExtension returned %q, %t, want %q", ext, ok, test.ext)
+			}
+			if ok && imageContentType(ext) != test.contentType {
+				t.Errorf("imageContentType(%q) = %q, want %q", ext, imageContentType(ext), test.contentType)
+			}
+		})
+	}
+}
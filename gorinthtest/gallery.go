@@ -0,0 +1,172 @@
+package gorinthtest
+
+import (
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+
+	"github.com/gearsdatapacks/gorinth"
+)
+
+func (s *Server) handleGallery(w http.ResponseWriter, r *http.Request, idOrSlug string) {
+	stored, _ := s.editableProject(w, r, idOrSlug)
+	if stored == nil {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		s.addGalleryImage(w, r, stored)
+	case http.MethodPatch:
+		s.modifyGalleryImage(w, r, stored)
+	case http.MethodDelete:
+		s.deleteGalleryImage(w, r, stored)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) addGalleryImage(w http.ResponseWriter, r *http.Request, stored *storedProject) {
+	query := r.URL.Query()
+
+	ext := query.Get("ext")
+	if ext == "" {
+		invalidInput(w, "missing field `ext`")
+		return
+	}
+	featured, err := strconv.ParseBool(query.Get("featured"))
+	if err != nil {
+		invalidInput(w, "missing field `featured`")
+		return
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		invalidInput(w, "%s", err)
+		return
+	}
+
+	file := s.storeFile(path.Join(stored.project.Id, "images", s.newId()+"."+ext), data)
+	image := gorinth.GalleryImage{
+		Url:      file.url,
+		Featured: featured,
+		Created:  now(),
+	}
+	if !applyGalleryQuery(w, r, &image) {
+		delete(s.files, file.url)
+		return
+	}
+
+	gallery := stored.project.Gallery
+	if featured {
+		gallery = unfeatureAll(gallery)
+	}
+	stored.setGallery(append(gallery, image))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) modifyGalleryImage(w http.ResponseWriter, r *http.Request, stored *storedProject) {
+	index := galleryIndex(w, r, stored)
+	if index == -1 {
+		return
+	}
+
+	gallery := append([]gorinth.GalleryImage{}, stored.project.Gallery...)
+	if rawFeatured := r.URL.Query().Get("featured"); rawFeatured != "" {
+		featured, err := strconv.ParseBool(rawFeatured)
+		if err != nil {
+			invalidInput(w, "featured: %s", err)
+			return
+		}
+		if featured {
+			gallery = unfeatureAll(gallery)
+		}
+		gallery[index].Featured = featured
+	}
+	if !applyGalleryQuery(w, r, &gallery[index]) {
+		return
+	}
+
+	stored.setGallery(gallery)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) deleteGalleryImage(w http.ResponseWriter, r *http.Request, stored *storedProject) {
+	index := galleryIndex(w, r, stored)
+	if index == -1 {
+		return
+	}
+
+	gallery := stored.project.Gallery
+	delete(s.files, gallery[index].Url)
+	stored.setGallery(append(append([]gorinth.GalleryImage{}, gallery[:index]...), gallery[index+1:]...))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Finds the gallery image given by the url query parameter,
+// writing an error response and returning -1 if it isn't in the project's gallery
+func galleryIndex(w http.ResponseWriter, r *http.Request, stored *storedProject) int {
+	url := r.URL.Query().Get("url")
+	for i, image := range stored.project.Gallery {
+		if image.Url == url {
+			return i
+		}
+	}
+
+	invalidInput(w, "Gallery item at URL %s is not part of the project's gallery.", url)
+	return -1
+}
+
+// Sets the title, description and ordering of a gallery image from the query parameters,
+// writing an error response and reporting false if they're invalid
+func applyGalleryQuery(w http.ResponseWriter, r *http.Request, image *gorinth.GalleryImage) bool {
+	query := r.URL.Query()
+
+	if query.Has("title") {
+		title := query.Get("title")
+		image.Title = &title
+	}
+	if query.Has("description") {
+		description := query.Get("description")
+		image.Description = &description
+	}
+	if query.Has("ordering") {
+		ordering, err := strconv.Atoi(query.Get("ordering"))
+		if err != nil {
+			invalidInput(w, "ordering: %s", err)
+			return false
+		}
+		image.Ordering = ordering
+	}
+	return true
+}
+
+// Replaces the project's gallery, keeping the images sorted by ordering and then title
+func (p *storedProject) setGallery(gallery []gorinth.GalleryImage) {
+	sort.SliceStable(gallery, func(i, j int) bool {
+		if gallery[i].Ordering != gallery[j].Ordering {
+			return gallery[i].Ordering < gallery[j].Ordering
+		}
+		return title(gallery[i]) < title(gallery[j])
+	})
+
+	p.project.Gallery = gallery
+}
+
+// Returns a copy of the gallery with no featured images, since only one image can be featured
+func unfeatureAll(gallery []gorinth.GalleryImage) []gorinth.GalleryImage {
+	result := append([]gorinth.GalleryImage{}, gallery...)
+	for i := range result {
+		result[i].Featured = false
+	}
+	return result
+}
+
+func title(image gorinth.GalleryImage) string {
+	if image.Title == nil {
+		return ""
+	}
+	return *image.Title
+}
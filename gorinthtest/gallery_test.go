@@ -0,0 +1,94 @@
+package gorinthtest_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gearsdatapacks/gorinth"
+)
+
+const (
+	pngImage = "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+	svgImage = `<svg xmlns="http://www.w3.org/2000/svg"></svg>`
+)
+
+func fetchFile(t *testing.T, client *gorinth.Client, url string) string {
+	t.Helper()
+
+	response, err := client.HTTPClient.Get(url)
+	if err != nil {
+		t.Fatalf("fetching %s failed: %s", url, err)
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil || response.StatusCode != http.StatusOK {
+		t.Fatalf("fetching %s returned status %d and error %v", url, response.StatusCode, err)
+	}
+	return string(data)
+}
+
+func TestGalleryImagesCanBeAddedModifiedAndDeleted(t *testing.T) {
+	_, client := newServer(t)
+	project := getProject(t, client, "public")
+
+	title := "Screenshot"
+	err := project.AddGalleryImage(gorinth.NewGalleryImage{Image: []byte(pngImage), Featured: true, Title: &title}, ownerToken)
+	if err != nil {
+		t.Fatalf("AddGalleryImage failed: %s", err)
+	}
+	if len(project.Gallery) != 1 || !strings.HasSuffix(project.Gallery[0].Url, ".png") || !project.Gallery[0].Featured {
+		t.Fatalf("gallery is %+v after adding a featured PNG", project.Gallery)
+	}
+	if data := fetchFile(t, client, project.Gallery[0].Url); data != pngImage {
+		t.Errorf("gallery image has contents %q, want the uploaded PNG", data)
+	}
+	screenshot := project.Gallery[0].Url
+
+	if err := project.AddGalleryImage(gorinth.NewGalleryImage{Image: []byte(svgImage)}, ownerToken); err != nil {
+		t.Fatalf("AddGalleryImage failed: %s", err)
+	}
+	if len(project.Gallery) != 2 {
+		t.Fatalf("gallery has %d images after adding an SVG, want 2", len(project.Gallery))
+	}
+
+	featured := false
+	description := "The main menu"
+	changes := gorinth.GalleryImageChanges{Featured: &featured, Description: &description}
+	if err := project.ModifyGalleryImage(screenshot, changes, ownerToken); err != nil {
+		t.Fatalf("ModifyGalleryImage failed: %s", err)
+	}
+
+	fetched := getProject(t, client, "public")
+	for _, image := range fetched.Gallery {
+		if image.Url != screenshot {
+			if !strings.HasSuffix(image.Url, ".svg") {
+				t.Errorf("SVG was stored at %s", image.Url)
+			}
+			continue
+		}
+		if image.Featured || image.Description == nil || *image.Description != description || *image.Title != title {
+			t.Errorf("modified image is %+v", image)
+		}
+	}
+
+	if err := project.DeleteGalleryImage(screenshot, ownerToken); err != nil {
+		t.Fatalf("DeleteGalleryImage failed: %s", err)
+	}
+	if len(project.Gallery) != 1 || project.Gallery[0].Url == screenshot {
+		t.Errorf("gallery is %+v after deleting the screenshot", project.Gallery)
+	}
+}
+
+func TestUnsupportedGalleryImageIsRejected(t *testing.T) {
+	_, client := newServer(t)
+	project := getProject(t, client, "public")
+
+	err := project.AddGalleryImage(gorinth.NewGalleryImage{Image: []byte("not an image")}, ownerToken)
+	if !errors.Is(err, gorinth.ErrInvalidInput) {
+		t.Errorf("got error %v, want ErrInvalidInput", err)
+	}
+}
@@ -0,0 +1,572 @@
+package gorinthtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gearsdatapacks/gorinth"
+)
+
+// A project stored in the emulator
+type storedProject struct {
+	project gorinth.Project
+	// The IDs of the users who are members of the project's team
+	members []string
+	// When the project is given its requested status, if it has been scheduled
+	scheduledAt time.Time
+}
+
+func (p *storedProject) isMember(user *gorinth.User) bool {
+	if user == nil {
+		return false
+	}
+	for _, member := range p.members {
+		if member == user.Id {
+			return true
+		}
+	}
+	return false
+}
+
+// Reports whether the project can be seen by the user, who may be nil
+func (p *storedProject) visibleTo(user *gorinth.User) bool {
+	switch p.project.Status {
+	case gorinth.ProjectApproved, gorinth.ProjectArchived, gorinth.ProjectUnlisted:
+		return true
+	}
+	return p.isMember(user)
+}
+
+// Converts the project to the JSON returned by the API
+func (p *storedProject) toJSON() map[string]any {
+	fields := toMap(p.project)
+	delete(fields, "Icon")
+	return fields
+}
+
+var slugPattern = regexp.MustCompile("^[\\w!@$()`.+,\"\\-']{3,64}$")
+
+// Adds a project to the emulator, owned by the user with the given ID.
+// Generates an ID, team and dates if they aren't set, and defaults the status to approved.
+// Panics if the slug is already taken
+func (s *Server) AddProject(project gorinth.Project, ownerId string) gorinth.Project {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if project.Status == "" {
+		project.Status = gorinth.ProjectApproved
+	}
+	if s.findProject(project.Slug) != nil || (project.Id != "" && s.findProject(project.Id) != nil) {
+		panic(fmt.Sprintf("gorinthtest: project %q already exists", project.Slug))
+	}
+
+	stored := s.storeProject(project, ownerId)
+	return stored.project
+}
+
+// Returns the project with the given ID or slug, as it is currently stored in the emulator
+func (s *Server) Project(idOrSlug string) (gorinth.Project, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stored := s.findProject(idOrSlug)
+	if stored == nil {
+		return gorinth.Project{}, false
+	}
+	return stored.project, true
+}
+
+func (s *Server) storeProject(project gorinth.Project, ownerId string) *storedProject {
+	if project.Id == "" {
+		project.Id = s.newId()
+	}
+	if project.Team == "" {
+		project.Team = s.newId()
+	}
+	if project.Published == "" {
+		project.Published = now()
+	}
+	if project.Updated == "" {
+		project.Updated = project.Published
+	}
+	if project.ProjectType == "" {
+		project.ProjectType = gorinth.ProjectMod
+	}
+	if project.Versions == nil {
+		project.Versions = []string{}
+	}
+	if project.Gallery == nil {
+		project.Gallery = []gorinth.GalleryImage{}
+	}
+	project.Icon = nil
+
+	stored := &storedProject{project: project, members: []string{ownerId}}
+	s.projects[project.Id] = stored
+	return stored
+}
+
+func (s *Server) findProject(idOrSlug string) *storedProject {
+	if stored, ok := s.projects[idOrSlug]; ok {
+		return stored
+	}
+
+	for _, stored := range s.projects {
+		if strings.EqualFold(stored.project.Slug, idOrSlug) {
+			return stored
+		}
+	}
+	return nil
+}
+
+// Finds a project which is visible to the user making the request,
+// writing an error response and returning nil if there isn't one
+func (s *Server) visibleProject(w http.ResponseWriter, r *http.Request, idOrSlug string) (*storedProject, *gorinth.User) {
+	user, ok := s.currentUser(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Authentication Error: Invalid Authentication Credentials")
+		return nil, nil
+	}
+
+	stored := s.findProject(idOrSlug)
+	if stored == nil || !stored.visibleTo(user) {
+		notFound(w)
+		return nil, nil
+	}
+	return stored, user
+}
+
+// Finds a project which the user making the request is a member of,
+// writing an error response and returning nil if there isn't one
+func (s *Server) editableProject(w http.ResponseWriter, r *http.Request, idOrSlug string) (*storedProject, *gorinth.User) {
+	user := s.requireUser(w, r)
+	if user == nil {
+		return nil, nil
+	}
+
+	stored := s.findProject(idOrSlug)
+	if stored == nil || !stored.visibleTo(user) {
+		notFound(w)
+		return nil, nil
+	}
+
+	if !stored.isMember(user) {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "You do not have permission to edit this project!")
+		return nil, nil
+	}
+	return stored, user
+}
+
+func (s *Server) handleProject(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 {
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w)
+			return
+		}
+		s.createProject(w, r)
+		return
+	}
+
+	if len(path) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			s.getProject(w, r, path[0])
+		case http.MethodPatch:
+			s.modifyProject(w, r, path[0])
+		case http.MethodDelete:
+			s.deleteProject(w, r, path[0])
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	switch {
+	case len(path) == 2 && path[1] == "check" && r.Method == http.MethodGet:
+		s.checkProject(w, r, path[0])
+	case len(path) == 2 && path[1] == "icon" && r.Method == http.MethodPatch:
+		s.changeIcon(w, r, path[0])
+	case len(path) == 2 && path[1] == "dependencies" && r.Method == http.MethodGet:
+		s.getDependencies(w, r, path[0])
+	case len(path) == 2 && path[1] == "follow":
+		s.handleFollow(w, r, path[0])
+	case len(path) == 2 && path[1] == "schedule" && r.Method == http.MethodPost:
+		s.scheduleProject(w, r, path[0])
+	case len(path) == 2 && path[1] == "gallery":
+		s.handleGallery(w, r, path[0])
+	case len(path) == 2 && path[1] == "version" && r.Method == http.MethodGet:
+		s.getProjectVersions(w, r, path[0])
+	default:
+		writeError(w, http.StatusNotFound, "not_found", "the requested route does not exist")
+	}
+}
+
+func (s *Server) handleProjects(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) != 0 || r.Method != http.MethodGet {
+		writeError(w, http.StatusNotFound, "not_found", "the requested route does not exist")
+		return
+	}
+
+	user, ok := s.currentUser(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Authentication Error: Invalid Authentication Credentials")
+		return
+	}
+
+	ids := []string{}
+	if err := json.Unmarshal([]byte(r.URL.Query().Get("ids")), &ids); err != nil {
+		invalidInput(w, "ids: %s", err)
+		return
+	}
+
+	response := []map[string]any{}
+	found := map[*storedProject]bool{}
+	for _, id := range ids {
+		stored := s.findProject(id)
+		if stored == nil || found[stored] || !stored.visibleTo(user) {
+			continue
+		}
+		found[stored] = true
+		response = append(response, stored.toJSON())
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) handleRandomProjects(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) != 0 || r.Method != http.MethodGet {
+		writeError(w, http.StatusNotFound, "not_found", "the requested route does not exist")
+		return
+	}
+
+	count, err := strconv.Atoi(r.URL.Query().Get("count"))
+	if err != nil || count < 1 || count > 100 {
+		invalidInput(w, "count: must be between 1 and 100")
+		return
+	}
+
+	candidates := []*storedProject{}
+	for _, stored := range s.projects {
+		if stored.project.Status == gorinth.ProjectApproved {
+			candidates = append(candidates, stored)
+		}
+	}
+	rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	if count < len(candidates) {
+		candidates = candidates[:count]
+	}
+
+	response := []map[string]any{}
+	for _, stored := range candidates {
+		response = append(response, stored.toJSON())
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) getProject(w http.ResponseWriter, r *http.Request, idOrSlug string) {
+	stored, _ := s.visibleProject(w, r, idOrSlug)
+	if stored == nil {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, stored.toJSON())
+}
+
+func (s *Server) checkProject(w http.ResponseWriter, r *http.Request, idOrSlug string) {
+	// Slugs are checked regardless of visibility, so that taken slugs are always reported
+	stored := s.findProject(idOrSlug)
+	if stored == nil {
+		notFound(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"id": stored.project.Id})
+}
+
+func (s *Server) createProject(w http.ResponseWriter, r *http.Request) {
+	user := s.requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		invalidInput(w, "invalid multipart body: %s", err)
+		return
+	}
+
+	data := struct {
+		gorinth.Project
+		LicenseId  string `json:"license_id"`
+		LicenseUrl string `json:"license_url"`
+		IsDraft    bool   `json:"is_draft"`
+	}{}
+	if err := json.Unmarshal([]byte(r.FormValue("data")), &data); err != nil {
+		invalidInput(w, "invalid project data: %s", err)
+		return
+	}
+
+	project := data.Project
+	if !slugPattern.MatchString(project.Slug) {
+		invalidInput(w, "slug: invalid slug %q", project.Slug)
+		return
+	}
+	if s.findProject(project.Slug) != nil {
+		invalidInput(w, "Slug collides with other project's id!")
+		return
+	}
+	if project.Title == "" {
+		invalidInput(w, "title: must be between 3 and 64 characters")
+		return
+	}
+
+	project.Id = ""
+	project.Team = ""
+	project.Published = ""
+	project.Updated = ""
+	project.Versions = nil
+	project.Gallery = nil
+	project.Downloads = 0
+	project.Followers = 0
+	project.Status = gorinth.ProjectDraft
+	if data.LicenseId != "" {
+		project.License = &gorinth.License{Id: data.LicenseId, Name: data.LicenseId}
+		if data.LicenseUrl != "" {
+			project.License.Url = &data.LicenseUrl
+		}
+	}
+
+	stored := s.storeProject(project, user.Id)
+
+	if icon, name, ok := formBytes(r, "icon"); ok {
+		s.storeIcon(stored, icon, strings.TrimPrefix(path.Ext(name), "."))
+	}
+
+	writeJSON(w, http.StatusOK, stored.toJSON())
+}
+
+// The fields of a project which can be changed by modifying it
+var editableProjectFields = map[string]bool{
+	"slug": true, "title": true, "description": true, "categories": true, "client_side": true,
+	"server_side": true, "body": true, "status": true, "requested_status": true,
+	"additional_categories": true, "issues_url": true, "source_url": true, "wiki_url": true,
+	"discord_url": true, "donation_urls": true, "license_id": true, "license_url": true,
+}
+
+func (s *Server) modifyProject(w http.ResponseWriter, r *http.Request, idOrSlug string) {
+	stored, _ := s.editableProject(w, r, idOrSlug)
+	if stored == nil {
+		return
+	}
+
+	changes := map[string]any{}
+	if err := json.NewDecoder(r.Body).Decode(&changes); err != nil {
+		invalidInput(w, "invalid JSON body: %s", err)
+		return
+	}
+
+	fields := map[string]any{}
+	for key, value := range changes {
+		if editableProjectFields[key] {
+			fields[key] = value
+		}
+	}
+
+	if slug, ok := fields["slug"].(string); ok {
+		if !slugPattern.MatchString(slug) {
+			invalidInput(w, "slug: invalid slug %q", slug)
+			return
+		}
+		if existing := s.findProject(slug); existing != nil && existing != stored {
+			invalidInput(w, "Slug collides with other project's id!")
+			return
+		}
+	}
+
+	if status, ok := fields["status"].(string); ok && !s.canChangeStatus(w, stored, gorinth.ProjectStatus(status)) {
+		return
+	}
+
+	project := stored.project
+	if licenseId, ok := fields["license_id"].(string); ok {
+		license := gorinth.License{Id: licenseId, Name: licenseId}
+		project.License = &license
+	}
+	if licenseUrl, ok := fields["license_url"]; ok && project.License != nil {
+		license := *project.License
+		license.Url = nil
+		if url, ok := licenseUrl.(string); ok {
+			license.Url = &url
+		}
+		project.License = &license
+	}
+	delete(fields, "license_id")
+	delete(fields, "license_url")
+
+	if err := applyFields(&project, fields); err != nil {
+		invalidInput(w, "%s", err)
+		return
+	}
+
+	project.Updated = now()
+	stored.project = project
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Reports whether a member may change the project to the status, writing an error if not
+func (s *Server) canChangeStatus(w http.ResponseWriter, stored *storedProject, status gorinth.ProjectStatus) bool {
+	approved := false
+	switch stored.project.Status {
+	case gorinth.ProjectApproved, gorinth.ProjectArchived, gorinth.ProjectUnlisted, gorinth.ProjectPrivate:
+		approved = true
+	}
+
+	allowed := false
+	switch status {
+	case gorinth.ProjectProcessing:
+		if len(stored.project.Versions) == 0 {
+			invalidInput(w, "Project submitted for review with no initial versions")
+			return false
+		}
+		allowed = !approved
+	case gorinth.ProjectApproved, gorinth.ProjectArchived, gorinth.ProjectUnlisted, gorinth.ProjectPrivate, gorinth.ProjectDraft:
+		allowed = approved
+	}
+
+	if !allowed {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Authentication Error: You don't have permission to set this status!")
+	}
+	return allowed
+}
+
+func (s *Server) deleteProject(w http.ResponseWriter, r *http.Request, idOrSlug string) {
+	stored, _ := s.editableProject(w, r, idOrSlug)
+	if stored == nil {
+		return
+	}
+
+	for _, versionId := range stored.project.Versions {
+		s.removeVersion(versionId)
+	}
+	if stored.project.IconUrl != nil {
+		delete(s.files, *stored.project.IconUrl)
+	}
+	for _, image := range stored.project.Gallery {
+		delete(s.files, image.Url)
+	}
+	delete(s.projects, stored.project.Id)
+	for userId, followed := range s.follows {
+		s.follows[userId] = remove(followed, stored.project.Id)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) scheduleProject(w http.ResponseWriter, r *http.Request, idOrSlug string) {
+	stored, _ := s.editableProject(w, r, idOrSlug)
+	if stored == nil {
+		return
+	}
+
+	data := struct {
+		Time            time.Time                      `json:"time"`
+		RequestedStatus gorinth.RequestedProjectStatus `json:"requested_status"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		invalidInput(w, "invalid JSON body: %s", err)
+		return
+	}
+
+	if data.Time.Before(time.Now()) {
+		invalidInput(w, "You cannot schedule a project to be released in the past!")
+		return
+	}
+
+	switch data.RequestedStatus {
+	case gorinth.RequestedProjectApproved, gorinth.RequestedProjectArchived, gorinth.RequestedProjectUnlisted,
+		gorinth.RequestedProjectPrivate, gorinth.RequestedProjectDraft:
+	default:
+		invalidInput(w, "Specified requested status cannot be requested!")
+		return
+	}
+
+	requested := data.RequestedStatus
+	stored.project.Status = gorinth.ProjectScheduled
+	stored.project.RequestedStatus = &requested
+	stored.scheduledAt = data.Time
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Gives scheduled projects their requested status once their scheduled time has passed
+func (s *Server) publishScheduled() {
+	for _, stored := range s.projects {
+		if stored.project.Status != gorinth.ProjectScheduled || stored.scheduledAt.After(time.Now()) {
+			continue
+		}
+
+		if stored.project.RequestedStatus != nil {
+			stored.project.Status = gorinth.ProjectStatus(*stored.project.RequestedStatus)
+		}
+		stored.scheduledAt = time.Time{}
+	}
+}
+
+func (s *Server) changeIcon(w http.ResponseWriter, r *http.Request, idOrSlug string) {
+	stored, _ := s.editableProject(w, r, idOrSlug)
+	if stored == nil {
+		return
+	}
+
+	ext := r.URL.Query().Get("ext")
+	if ext == "" {
+		invalidInput(w, "missing field `ext`")
+		return
+	}
+
+	icon, err := io.ReadAll(r.Body)
+	if err != nil {
+		invalidInput(w, "%s", err)
+		return
+	}
+
+	s.storeIcon(stored, icon, ext)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) storeIcon(stored *storedProject, icon []byte, ext string) {
+	if ext == "" {
+		ext = "png"
+	}
+
+	file := s.storeFile(path.Join(stored.project.Id, "icon."+ext), icon)
+	stored.project.IconUrl = &file.url
+}
+
+// Reads a part of a multipart form, which may either be a file or a plain value
+func formBytes(r *http.Request, key string) (data []byte, filename string, ok bool) {
+	if files := r.MultipartForm.File[key]; len(files) != 0 {
+		file, err := files[0].Open()
+		if err != nil {
+			return nil, "", false
+		}
+		defer file.Close()
+
+		data, err = io.ReadAll(file)
+		return data, files[0].Filename, err == nil
+	}
+
+	if values := r.MultipartForm.Value[key]; len(values) != 0 {
+		return []byte(values[0]), "", true
+	}
+
+	return nil, "", false
+}
@@ -0,0 +1,141 @@
+package gorinthtest_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gearsdatapacks/gorinth"
+)
+
+func TestGetProjectsFetchesInChunks(t *testing.T) {
+	server, client := newServer(t)
+	owner, err := client.GetUserFromAuth(ownerToken)
+	if err != nil {
+		t.Fatalf("GetUserFromAuth failed: %s", err)
+	}
+	public, _ := server.Project("public")
+
+	// Enough IDs that they don't fit in one URL
+	ids := []string{}
+	for i := 0; i < 300; i++ {
+		stored := server.AddProject(gorinth.Project{Slug: fmt.Sprintf("project-%d", i), Title: "Project"}, owner.Id)
+		ids = append(ids, stored.Id)
+	}
+	requested := append(ids, ids[0], public.Id, "public", "PUBLIC", "unknown")
+
+	counter := countRequests(client)
+	projects, err := client.GetProjects(requested, ownerToken)
+	if err != nil {
+		t.Fatalf("GetProjects failed: %s", err)
+	}
+	if counter.requests != 2 {
+		t.Errorf("GetProjects sent %d requests, want 2", counter.requests)
+	}
+
+	for _, id := range ids {
+		if project, ok := projects[id]; !ok || project.Id != id {
+			t.Errorf("results for %s are %+v, want the project with that ID", id, project)
+		}
+	}
+	for _, key := range []string{public.Id, "public", "PUBLIC"} {
+		if project, ok := projects[key]; !ok || project.Id != public.Id {
+			t.Errorf("results for %q are %+v, want the public project", key, project)
+		}
+	}
+	if _, ok := projects["unknown"]; ok {
+		t.Error("results include the unknown project")
+	}
+	if len(projects) != len(ids)+3 {
+		t.Errorf("got %d results, want %d", len(projects), len(ids)+3)
+	}
+}
+
+func TestDeletingDownloadedProjectIsRefused(t *testing.T) {
+	server, client := newServer(t)
+	owner, err := client.GetUserFromAuth(ownerToken)
+	if err != nil {
+		t.Fatalf("GetUserFromAuth failed: %s", err)
+	}
+	server.AddProject(gorinth.Project{Slug: "popular", Title: "Popular", Downloads: 10}, owner.Id)
+	project := getProject(t, client, "popular")
+	counter := countRequests(client)
+
+	if err := project.Delete(ownerToken); !errors.Is(err, gorinth.ErrSafetyCheck) {
+		t.Errorf("got error %v, want ErrSafetyCheck", err)
+	}
+	if deletes := counter.methods[http.MethodDelete]; deletes != 0 {
+		t.Errorf("sent %d DELETE requests before refusing", deletes)
+	}
+
+	if err := project.Delete(ownerToken, gorinth.Force()); err != nil {
+		t.Fatalf("forced Delete failed: %s", err)
+	}
+	if _, err := client.GetProject("popular", ownerToken); !errors.Is(err, gorinth.ErrNotFound) {
+		t.Errorf("GetProject of the deleted project returned %v, want ErrNotFound", err)
+	}
+}
+
+func TestDeletingProjectWithoutDownloadsSucceeds(t *testing.T) {
+	_, client := newServer(t)
+	project := getProject(t, client, "public")
+
+	if err := project.Delete(ownerToken); err != nil {
+		t.Fatalf("Delete failed: %s", err)
+	}
+	if _, err := client.GetProject("public", ownerToken); !errors.Is(err, gorinth.ErrNotFound) {
+		t.Errorf("GetProject of the deleted project returned %v, want ErrNotFound", err)
+	}
+}
+
+func TestDeletingProjectWithoutPermissionFails(t *testing.T) {
+	tests := []struct {
+		name string
+		slug string
+		want error
+	}{
+		{"public project", "public", gorinth.ErrUnauthorized},
+		{"hidden project", "hidden", gorinth.ErrNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, client := newServer(t)
+			project := getProject(t, client, test.slug)
+
+			// Skip the safety check, so the DELETE response is the one returned
+			err := project.Delete(strangerToken, gorinth.Force())
+			if !errors.Is(err, test.want) {
+				t.Errorf("got error %v, want %v", err, test.want)
+			}
+			if _, err := client.GetProject(test.slug, ownerToken); err != nil {
+				t.Errorf("GetProject failed after a rejected delete: %s", err)
+			}
+		})
+	}
+}
+
+func TestPatchChangesProject(t *testing.T) {
+	_, client := newServer(t)
+	project := getProject(t, client, "public")
+
+	patch := new(gorinth.ProjectPatch).SetDiscordUrl("https://discord.gg/test").SetAdditionalCategories([]string{"utility"})
+	if err := project.Patch(patch, ownerToken); err != nil {
+		t.Fatalf("Patch failed: %s", err)
+	}
+	if project.DiscordUrl == nil || *project.DiscordUrl != "https://discord.gg/test" {
+		t.Errorf("Patch didn't refresh the project's discord URL, got %v", project.DiscordUrl)
+	}
+
+	patch = new(gorinth.ProjectPatch).ClearDiscordUrl().ClearAdditionalCategories()
+	if err := project.Patch(patch, ownerToken); err != nil {
+		t.Fatalf("Patch failed: %s", err)
+	}
+
+	patched := getProject(t, client, "public")
+	if patched.DiscordUrl != nil || len(patched.AdditionalCategories) != 0 {
+		t.Errorf("GetProject returned discord URL %v and additional categories %v after clearing them",
+			patched.DiscordUrl, patched.AdditionalCategories)
+	}
+}
@@ -0,0 +1,285 @@
+package gorinthtest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Whether a Recorder records new interactions or replays existing ones
+type Mode int
+
+const (
+	// Sends requests to the real API, recording each interaction
+	ModeRecord Mode = iota
+	// Serves recorded interactions without sending any requests
+	ModeReplay
+)
+
+// Returned when replaying a request which doesn't match any unused recorded interaction
+var ErrUnmatchedRequest = errors.New("gorinthtest: no recorded interaction matches the request")
+
+// The boundary that multipart bodies are rewritten to use, since the real one is random
+const fixtureBoundary = "gorinthtest-boundary"
+
+// Headers which are never written to fixture files
+var scrubbedHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// An http.RoundTripper which records HTTP interactions to a fixture file,
+// and replays them later without sending any requests.
+// Use it as the transport of a client's HTTPClient:
+//
+//	recorder, err := gorinthtest.NewRecorder("testdata/project.json", gorinthtest.ModeReplay)
+//	client.HTTPClient = &http.Client{Transport: recorder}
+//	defer recorder.Close()
+type Recorder struct {
+	// The fixture file that interactions are stored in
+	Path string
+	// Whether interactions are recorded or replayed
+	Mode Mode
+	// The transport used to send requests while recording. Defaults to http.DefaultTransport
+	Transport http.RoundTripper
+
+	mutex        sync.Mutex
+	interactions []*Interaction
+}
+
+// A recorded request and the response it received
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+	replayed bool
+}
+
+// A request in a fixture file, with sensitive headers removed
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header"`
+	Body   fixtureBody `json:"body"`
+}
+
+// A response in a fixture file, with sensitive headers removed
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       fixtureBody `json:"body"`
+}
+
+// A body stored in a fixture file. Written as a plain string if it is valid UTF-8,
+// so that fixtures can be read and diffed, or as base64 otherwise
+type fixtureBody []byte
+
+func (body fixtureBody) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(body) {
+		return json.Marshal(string(body))
+	}
+	return json.Marshal(map[string]string{"base64": base64.StdEncoding.EncodeToString(body)})
+}
+
+func (body *fixtureBody) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*body = fixtureBody(text)
+		return nil
+	}
+
+	encoded := struct {
+		Base64 string `json:"base64"`
+	}{}
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(encoded.Base64)
+	*body = decoded
+	return err
+}
+
+// Creates a recorder using the given fixture file. In replay mode,
+// the interactions are loaded from the file, which must exist
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	recorder := &Recorder{Path: path, Mode: mode}
+	if mode != ModeReplay {
+		return recorder, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &recorder.interactions); err != nil {
+		return nil, fmt.Errorf("gorinthtest: invalid fixture file %s: %w", path, err)
+	}
+
+	return recorder, nil
+}
+
+// Saves the recorded interactions to the fixture file. Does nothing in replay mode
+func (r *Recorder) Close() error {
+	if r.Mode != ModeRecord {
+		return nil
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.Path, data, 0o644)
+}
+
+// Returns the number of recorded interactions which haven't been replayed yet
+func (r *Recorder) Unused() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	unused := 0
+	for _, interaction := range r.interactions {
+		if !interaction.replayed {
+			unused++
+		}
+	}
+	return unused
+}
+
+func (r *Recorder) RoundTrip(request *http.Request) (*http.Response, error) {
+	body, err := readBody(request)
+	if err != nil {
+		return nil, err
+	}
+	recorded := recordRequest(request, body)
+
+	if r.Mode == ModeReplay {
+		return r.replay(request, recorded)
+	}
+
+	// A RoundTripper mustn't modify the request, so send a copy with the body which has been read
+	clone := request.Clone(request.Context())
+	if request.Body != nil {
+		clone.Body = io.NopCloser(bytes.NewReader(body))
+		clone.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+		clone.ContentLength = int64(len(body))
+	}
+	return r.record(clone, recorded)
+}
+
+func (r *Recorder) record(request *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	response, err := transport.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	response.Body = io.NopCloser(bytes.NewReader(body))
+
+	r.mutex.Lock()
+	r.interactions = append(r.interactions, &Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			StatusCode: response.StatusCode,
+			Header:     scrub(response.Header),
+			Body:       body,
+		},
+	})
+	r.mutex.Unlock()
+
+	return response, nil
+}
+
+func (r *Recorder) replay(request *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, interaction := range r.interactions {
+		if interaction.replayed || !interaction.Request.matches(recorded) {
+			continue
+		}
+		interaction.replayed = true
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       request,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrUnmatchedRequest, recorded.Method, recorded.URL)
+}
+
+func (recorded RecordedRequest) matches(other RecordedRequest) bool {
+	return recorded.Method == other.Method &&
+		recorded.URL == other.URL &&
+		bytes.Equal(recorded.Body, other.Body)
+}
+
+// Reads and closes the body of a request, which may be nil
+func readBody(request *http.Request) ([]byte, error) {
+	if request.Body == nil {
+		return nil, nil
+	}
+
+	defer request.Body.Close()
+	return io.ReadAll(request.Body)
+}
+
+// Converts a request with the given body into the form stored in fixture files
+func recordRequest(request *http.Request, body []byte) RecordedRequest {
+	header := scrub(request.Header)
+
+	// Multipart boundaries are random, so replace them with a fixed one
+	// to allow uploads to be matched when replaying
+	mediaType, params, err := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	if err == nil && strings.HasPrefix(mediaType, "multipart/") && params["boundary"] != "" {
+		body = bytes.ReplaceAll(body, []byte(params["boundary"]), []byte(fixtureBoundary))
+		params["boundary"] = fixtureBoundary
+		header.Set("Content-Type", mime.FormatMediaType(mediaType, params))
+	}
+
+	return RecordedRequest{
+		Method: request.Method,
+		URL:    request.URL.String(),
+		Header: header,
+		Body:   body,
+	}
+}
+
+// Returns a copy of the headers with sensitive values removed
+func scrub(header http.Header) http.Header {
+	scrubbed := header.Clone()
+	if scrubbed == nil {
+		scrubbed = http.Header{}
+	}
+	for _, key := range scrubbedHeaders {
+		scrubbed.Del(key)
+	}
+	return scrubbed
+}
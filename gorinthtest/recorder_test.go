@@ -0,0 +1,148 @@
+package gorinthtest_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gearsdatapacks/gorinth"
+	"github.com/gearsdatapacks/gorinth/gorinthtest"
+)
+
+// Writes a file with the given contents to a new temporary directory, returning its path
+func writeTempFile(t *testing.T, name string, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// Fetches a project, uploads a version with the file at the path, then lists the project's versions
+func createVersion(client *gorinth.Client, path string) ([]gorinth.Version, error) {
+	project, err := client.GetProject("public", ownerToken)
+	if err != nil {
+		return nil, err
+	}
+
+	err = project.CreateVersion(gorinth.Version{
+		Name:          "First release",
+		VersionNumber: "1.0.0",
+		VersionType:   gorinth.ReleaseRelease,
+		FileParts:     []string{path},
+	}, ownerToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return project.GetVersions()
+}
+
+// Records a version upload against the emulator, returning the fixture and the client used
+func recordFixture(t *testing.T) (string, *gorinth.Client) {
+	t.Helper()
+
+	server, client := newServer(t)
+	fixture := filepath.Join(t.TempDir(), "fixture.json")
+	recorder := &gorinthtest.Recorder{Path: fixture, Mode: gorinthtest.ModeRecord, Transport: server.Client().HTTPClient.Transport}
+	client.HTTPClient = &http.Client{Transport: recorder}
+
+	versions, err := createVersion(client, writeTempFile(t, "mod.jar", "jar contents"))
+	if err != nil {
+		t.Fatalf("recording failed: %s", err)
+	}
+	if len(versions) != 1 {
+		t.Fatalf("recorded %d versions, want 1", len(versions))
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("saving the fixture failed: %s", err)
+	}
+
+	// Replaying must work without the server
+	server.Close()
+	return fixture, client
+}
+
+func TestRecordedUploadIsReplayed(t *testing.T) {
+	fixture, client := recordFixture(t)
+
+	data, err := os.ReadFile(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), ownerToken) {
+		t.Error("fixture contains the auth token")
+	}
+
+	recorder, err := gorinthtest.NewRecorder(fixture, gorinthtest.ModeReplay)
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %s", err)
+	}
+	client.HTTPClient = &http.Client{Transport: recorder}
+
+	// The same file in a different directory, as on another machine
+	versions, err := createVersion(client, writeTempFile(t, "mod.jar", "jar contents"))
+	if err != nil {
+		t.Fatalf("replaying failed: %s", err)
+	}
+	if len(versions) != 1 || versions[0].VersionNumber != "1.0.0" {
+		t.Errorf("replayed versions %+v, want the recorded version", versions)
+	}
+	if unused := recorder.Unused(); unused != 0 {
+		t.Errorf("%d recorded interactions weren't replayed", unused)
+	}
+}
+
+func TestUnmatchedRequestFails(t *testing.T) {
+	fixture, client := recordFixture(t)
+
+	recorder, err := gorinthtest.NewRecorder(fixture, gorinthtest.ModeReplay)
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %s", err)
+	}
+	client.HTTPClient = &http.Client{Transport: recorder}
+
+	// The upload differs from the recorded one, so it can't be replayed
+	_, err = createVersion(client, writeTempFile(t, "mod.jar", "different contents"))
+	if !errors.Is(err, gorinthtest.ErrUnmatchedRequest) {
+		t.Errorf("got error %v, want ErrUnmatchedRequest", err)
+	}
+
+	_, err = client.GetProject("unknown", "")
+	if !errors.Is(err, gorinthtest.ErrUnmatchedRequest) {
+		t.Errorf("got error %v, want ErrUnmatchedRequest", err)
+	}
+}
+
+func TestRecorderDoesNotModifyRequest(t *testing.T) {
+	server := gorinthtest.NewServer()
+	defer server.Close()
+
+	recorder := &gorinthtest.Recorder{Path: filepath.Join(t.TempDir(), "fixture.json"), Mode: gorinthtest.ModeRecord}
+	body := io.NopCloser(bytes.NewReader([]byte("{}")))
+	request, err := http.NewRequest(http.MethodPost, server.URL+"/project", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := request.Header.Clone()
+
+	response, err := recorder.RoundTrip(request)
+	if err != nil {
+		t.Fatalf("RoundTrip failed: %s", err)
+	}
+	response.Body.Close()
+
+	if request.Body != body {
+		t.Error("RoundTrip replaced the request's body")
+	}
+	if len(request.Header) != len(header) {
+		t.Errorf("RoundTrip changed the request's headers to %v", request.Header)
+	}
+}
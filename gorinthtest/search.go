@@ -0,0 +1,260 @@
+package gorinthtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gearsdatapacks/gorinth"
+)
+
+// Converts a project to the result returned when searching for it
+func (s *Server) searchResult(project *storedProject) map[string]any {
+	p := project.project
+
+	author := ""
+	if owner := s.users[project.members[0]]; owner != nil {
+		author = owner.Username
+	}
+
+	license := ""
+	if p.License != nil {
+		license = p.License.Id
+	}
+
+	gallery := []string{}
+	var featured *string
+	for _, image := range p.Gallery {
+		gallery = append(gallery, image.Url)
+		if image.Featured && featured == nil {
+			url := image.Url
+			featured = &url
+		}
+	}
+
+	var latestVersion *string
+	if len(p.GameVersions) != 0 {
+		latestVersion = &p.GameVersions[len(p.GameVersions)-1]
+	}
+
+	categories := append(append([]string{}, p.Categories...), p.Loaders...)
+
+	return toMap(gorinth.SearchResult{
+		Slug:               p.Slug,
+		Title:              p.Title,
+		Description:        p.Description,
+		Categories:         categories,
+		ClientSide:         p.ClientSide,
+		ServerSide:         p.ServerSide,
+		ProjectType:        p.ProjectType,
+		Downloads:          p.Downloads,
+		IconUrl:            p.IconUrl,
+		Color:              p.Color,
+		ThreadId:           p.ThreadId,
+		MonetizationStatus: p.MonetizationStatus,
+		Versions:           p.GameVersions,
+		ProjectId:          p.Id,
+		Author:             author,
+		DisplayCategories:  categories,
+		Follows:            p.Followers,
+		DateCreated:        p.Published,
+		DateModified:       p.Updated,
+		LatestVersion:      latestVersion,
+		License:            license,
+		Gallery:            gallery,
+		FeaturedGallery:    featured,
+	})
+}
+
+// The operators which can be used in facets, longest first so that "!=" isn't read as "="
+var facetOperators = []string{"!=", ">=", "<=", ":", "=", "<", ">"}
+
+// Reports whether a search result matches a facet such as "categories:fabric"
+func matchesFacet(result map[string]any, facet string) bool {
+	for _, operator := range facetOperators {
+		index := strings.Index(facet, operator)
+		if index == -1 {
+			continue
+		}
+
+		key, expected := facet[:index], facet[index+len(operator):]
+
+		// List fields match if any of their values match
+		if values, ok := result[key].([]any); ok {
+			for _, value := range values {
+				if compareFacet(value, ":", expected) {
+					return operator != "!="
+				}
+			}
+			return operator == "!="
+		}
+		return compareFacet(result[key], operator, expected)
+	}
+	return false
+}
+
+func compareFacet(value any, operator string, expected string) bool {
+	if number, ok := value.(float64); ok {
+		target, err := strconv.ParseFloat(expected, 64)
+		if err != nil {
+			return false
+		}
+
+		switch operator {
+		case ":", "=":
+			return number == target
+		case "!=":
+			return number != target
+		case "<":
+			return number < target
+		case "<=":
+			return number <= target
+		case ">":
+			return number > target
+		case ">=":
+			return number >= target
+		}
+		return false
+	}
+
+	str, _ := value.(string)
+	switch operator {
+	case ":", "=":
+		return strings.EqualFold(str, expected)
+	case "!=":
+		return !strings.EqualFold(str, expected)
+	}
+	return false
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) != 0 || r.Method != http.MethodGet {
+		writeError(w, http.StatusNotFound, "not_found", "the requested route does not exist")
+		return
+	}
+
+	query := r.URL.Query()
+
+	facets := [][]string{}
+	if rawFacets := query.Get("facets"); rawFacets != "" {
+		if err := json.Unmarshal([]byte(rawFacets), &facets); err != nil {
+			invalidInput(w, "invalid facets: %s", err)
+			return
+		}
+	}
+
+	offset, limit := 0, 10
+	if rawOffset := query.Get("offset"); rawOffset != "" {
+		var err error
+		if offset, err = strconv.Atoi(rawOffset); err != nil || offset < 0 {
+			invalidInput(w, "invalid offset %q", rawOffset)
+			return
+		}
+	}
+	if rawLimit := query.Get("limit"); rawLimit != "" {
+		var err error
+		if limit, err = strconv.Atoi(rawLimit); err != nil || limit < 0 || limit > 100 {
+			invalidInput(w, "invalid limit %q", rawLimit)
+			return
+		}
+	}
+
+	text := strings.ToLower(query.Get("query"))
+	hits := []map[string]any{}
+
+	for _, project := range s.projects {
+		status := project.project.Status
+		if status != gorinth.ProjectApproved && status != gorinth.ProjectArchived {
+			continue
+		}
+
+		p := project.project
+		if text != "" &&
+			!strings.Contains(strings.ToLower(p.Title), text) &&
+			!strings.Contains(strings.ToLower(p.Description), text) &&
+			!strings.Contains(strings.ToLower(p.Slug), text) {
+			continue
+		}
+
+		result := s.searchResult(project)
+		if matchesAllFacets(result, facets) {
+			hits = append(hits, result)
+		}
+	}
+
+	sortHits(hits, gorinth.SearchIndex(query.Get("index")))
+	totalHits := len(hits)
+
+	if offset > len(hits) {
+		offset = len(hits)
+	}
+	hits = hits[offset:]
+	if limit < len(hits) {
+		hits = hits[:limit]
+	}
+
+	writeJSON(w, http.StatusOK, gorinthSearchResponse{
+		Hits:      hits,
+		Offset:    offset,
+		Limit:     limit,
+		TotalHits: totalHits,
+	})
+}
+
+// Matches gorinth.SearchResponse, with hits kept as JSON objects
+type gorinthSearchResponse struct {
+	Hits      []map[string]any `json:"hits"`
+	Offset    int              `json:"offset"`
+	Limit     int              `json:"limit"`
+	TotalHits int              `json:"total_hits"`
+}
+
+// Facets in the same list are ORed together, and the lists are ANDed together
+func matchesAllFacets(result map[string]any, facets [][]string) bool {
+	for _, facetList := range facets {
+		matched := false
+		for _, facet := range facetList {
+			if matchesFacet(result, facet) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func sortHits(hits []map[string]any, index gorinth.SearchIndex) {
+	// Sort by ID first, so that results are in a consistent order
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i]["project_id"].(string) < hits[j]["project_id"].(string)
+	})
+
+	var key string
+	switch index {
+	case gorinth.IndexDownloads:
+		key = "downloads"
+	case gorinth.IndexFollows:
+		key = "follows"
+	case gorinth.IndexNewest:
+		key = "date_created"
+	case gorinth.IndexUpdated:
+		key = "date_modified"
+	default:
+		return
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		switch a := hits[i][key].(type) {
+		case float64:
+			return a > hits[j][key].(float64)
+		case string:
+			return a > hits[j][key].(string)
+		}
+		return false
+	})
+}
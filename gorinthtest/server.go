@@ -0,0 +1,196 @@
+// Package gorinthtest provides an in-memory emulator of the Modrinth API,
+// for testing code which uses gorinth without sending requests to Modrinth
+package gorinthtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gearsdatapacks/gorinth"
+)
+
+// An in-memory emulator of the Modrinth API, backed by an httptest.Server.
+// All state is kept in memory and lost when the server is closed
+type Server struct {
+	// The base URL of the emulated API, such as "http://127.0.0.1:1234"
+	URL    string
+	server *httptest.Server
+
+	mutex    sync.Mutex
+	nextId   uint64
+	users    map[string]*gorinth.User
+	tokens   map[string]string
+	projects map[string]*storedProject
+	versions map[string]*storedVersion
+	files    map[string]*storedFile
+	// The IDs of the projects each user follows, keyed by user ID
+	follows map[string][]string
+}
+
+// Starts a new emulator with no users or projects
+func NewServer() *Server {
+	s := &Server{
+		nextId:   1,
+		users:    map[string]*gorinth.User{},
+		tokens:   map[string]string{},
+		projects: map[string]*storedProject{},
+		versions: map[string]*storedVersion{},
+		files:    map[string]*storedFile{},
+		follows:  map[string][]string{},
+	}
+	s.server = httptest.NewServer(s)
+	s.URL = s.server.URL
+
+	return s
+}
+
+// Shuts down the emulator
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Returns a client which sends all requests to the emulator
+func (s *Server) Client() *gorinth.Client {
+	client := gorinth.NewClient()
+	client.Environment = gorinth.CustomEnvironment(s.URL)
+	client.HTTPClient = s.server.Client()
+	client.UserAgent = gorinth.UserAgent{Name: "gorinthtest"}
+
+	return client
+}
+
+// Generates a new base62 ID, in the same format as Modrinth's IDs
+func (s *Server) newId() string {
+	const alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+	id := s.nextId
+	s.nextId++
+
+	encoded := make([]byte, 8)
+	for i := len(encoded) - 1; i >= 0; i-- {
+		encoded[i] = alphabet[id%62]
+		id /= 62
+	}
+	return string(encoded)
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339Nano)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.publishScheduled()
+
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	route := routes[path[0]]
+	if route == nil {
+		writeError(w, http.StatusNotFound, "not_found", "the requested route does not exist")
+		return
+	}
+
+	route(s, w, r, path[1:])
+}
+
+// Handles requests whose path starts with a particular segment, given the rest of the path
+type routeHandler func(s *Server, w http.ResponseWriter, r *http.Request, path []string)
+
+var routes map[string]routeHandler
+
+func init() {
+	routes = map[string]routeHandler{
+		"search":          (*Server).handleSearch,
+		"project":         (*Server).handleProject,
+		"projects":        (*Server).handleProjects,
+		"projects_random": (*Server).handleRandomProjects,
+		"version":         (*Server).handleVersion,
+		"version_file":    (*Server).handleVersionFile,
+		"user":            (*Server).handleUser,
+		"data":            (*Server).handleData,
+	}
+}
+
+// Returns the user whose token authorised the request, or nil if no token was given.
+// Reports false if the token is invalid
+func (s *Server) currentUser(r *http.Request) (*gorinth.User, bool) {
+	token := r.Header.Get("Authorization")
+	if token == "" {
+		return nil, true
+	}
+
+	userId, ok := s.tokens[token]
+	if !ok {
+		return nil, false
+	}
+	return s.users[userId], true
+}
+
+// Returns the user whose token authorised the request,
+// writing an error response and returning nil if there isn't one
+func (s *Server) requireUser(w http.ResponseWriter, r *http.Request) *gorinth.User {
+	user, ok := s.currentUser(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Authentication Error: Invalid Authentication Credentials")
+		return nil
+	}
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Authentication Error: No authorization header provided")
+		return nil
+	}
+	return user
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, kind string, description string, values ...any) {
+	writeJSON(w, status, map[string]string{
+		"error":       kind,
+		"description": fmt.Sprintf(description, values...),
+	})
+}
+
+func notFound(w http.ResponseWriter) {
+	writeError(w, http.StatusNotFound, "not_found", "The requested item(s) were not found or no authorization to access the requested item(s)")
+}
+
+func invalidInput(w http.ResponseWriter, description string, values ...any) {
+	writeError(w, http.StatusBadRequest, "invalid_input", "Error while validating input: "+description, values...)
+}
+
+func methodNotAllowed(w http.ResponseWriter) {
+	writeError(w, http.StatusMethodNotAllowed, "invalid_input", "the requested method is not allowed on this route")
+}
+
+// Converts a value to a JSON object, so that fields can be added or removed
+func toMap(value any) map[string]any {
+	encoded, _ := json.Marshal(value)
+	result := map[string]any{}
+	json.Unmarshal(encoded, &result)
+
+	return result
+}
+
+// Overwrites the fields of a value with the ones given in a JSON object
+func applyFields(value any, fields map[string]any) error {
+	merged := toMap(value)
+	for key, field := range fields {
+		merged[key] = field
+	}
+
+	encoded, err := json.Marshal(merged)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded, value)
+}
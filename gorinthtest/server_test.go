@@ -0,0 +1,213 @@
+package gorinthtest_test
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gearsdatapacks/gorinth"
+	"github.com/gearsdatapacks/gorinth/gorinthtest"
+)
+
+const (
+	ownerToken    = "owner-token"
+	strangerToken = "stranger-token"
+)
+
+// Starts an emulator with an owner who has a public and a draft project,
+// and a stranger who isn't a member of either
+func newServer(t *testing.T) (*gorinthtest.Server, *gorinth.Client) {
+	t.Helper()
+
+	server := gorinthtest.NewServer()
+	t.Cleanup(server.Close)
+
+	owner := server.AddUser(gorinth.User{Username: "owner"}, ownerToken)
+	server.AddUser(gorinth.User{Username: "stranger"}, strangerToken)
+	server.AddProject(gorinth.Project{Slug: "public", Title: "Public", ProjectType: gorinth.ProjectMod}, owner.Id)
+	server.AddProject(gorinth.Project{Slug: "hidden", Title: "Hidden", Status: gorinth.ProjectDraft}, owner.Id)
+
+	return server, server.Client()
+}
+
+func getProject(t *testing.T, client *gorinth.Client, slug string) *gorinth.Project {
+	t.Helper()
+
+	project, err := client.GetProject(slug, ownerToken)
+	if err != nil {
+		t.Fatalf("GetProject(%q) failed: %s", slug, err)
+	}
+	return project
+}
+
+func TestCreatedVersionIsListed(t *testing.T) {
+	_, client := newServer(t)
+	project := getProject(t, client, "public")
+
+	path := filepath.Join(t.TempDir(), "mod.jar")
+	if err := os.WriteFile(path, []byte("jar"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := project.CreateVersion(gorinth.Version{
+		Name:          "First release",
+		VersionNumber: "1.0.0",
+		VersionType:   gorinth.ReleaseRelease,
+		FileParts:     []string{path},
+	}, ownerToken)
+	if err != nil {
+		t.Fatalf("CreateVersion failed: %s", err)
+	}
+
+	latest, err := project.GetLatestVersion()
+	if err != nil {
+		t.Fatalf("GetLatestVersion failed: %s", err)
+	}
+	if latest.VersionNumber != "1.0.0" || len(latest.Files) != 1 || latest.Files[0].Filename != "mod.jar" {
+		t.Errorf("GetLatestVersion returned %+v, want the created version", latest)
+	}
+}
+
+func TestModifyChangesProject(t *testing.T) {
+	_, client := newServer(t)
+	project := getProject(t, client, "public")
+
+	if err := project.Modify(gorinth.Project{Title: "Renamed", Description: "New description"}, ownerToken); err != nil {
+		t.Fatalf("Modify failed: %s", err)
+	}
+
+	modified := getProject(t, client, "public")
+	if modified.Title != "Renamed" || modified.Description != "New description" {
+		t.Errorf("GetProject returned title %q and description %q after Modify", modified.Title, modified.Description)
+	}
+}
+
+func TestUnauthorizedChangesAreRejected(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"missing token", ""},
+		{"bad token", "not-a-token"},
+		{"non-member", strangerToken},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, client := newServer(t)
+			project := getProject(t, client, "public")
+
+			err := project.Modify(gorinth.Project{Title: "Stolen"}, test.token)
+			if !errors.Is(err, gorinth.ErrUnauthorized) {
+				t.Errorf("Modify returned %v, want ErrUnauthorized", err)
+			}
+
+			var apiError *gorinth.APIError
+			if !errors.As(err, &apiError) || apiError.StatusCode != 401 {
+				t.Errorf("Modify returned %v, want a 401 APIError", err)
+			}
+
+			if unchanged := getProject(t, client, "public"); unchanged.Title != "Public" {
+				t.Errorf("project was renamed to %q by an unauthorized request", unchanged.Title)
+			}
+		})
+	}
+}
+
+func TestMissingProjectsAreNotFound(t *testing.T) {
+	tests := []struct {
+		name  string
+		slug  string
+		token string
+	}{
+		{"unknown project", "unknown", ownerToken},
+		{"hidden project without a token", "hidden", ""},
+		{"hidden project as a non-member", "hidden", strangerToken},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, client := newServer(t)
+
+			_, err := client.GetProject(test.slug, test.token)
+			if !errors.Is(err, gorinth.ErrNotFound) {
+				t.Errorf("GetProject returned %v, want ErrNotFound", err)
+			}
+		})
+	}
+
+	_, client := newServer(t)
+	if _, err := client.GetProject("hidden", ownerToken); err != nil {
+		t.Errorf("GetProject of a hidden project as its owner failed: %s", err)
+	}
+}
+
+// Counts the requests sent through it, in total and by method
+type countingTransport struct {
+	transport http.RoundTripper
+	requests  int
+	methods   map[string]int
+}
+
+func (t *countingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	t.requests++
+	if t.methods == nil {
+		t.methods = map[string]int{}
+	}
+	t.methods[request.Method]++
+	return t.transport.RoundTrip(request)
+}
+
+// Replaces the client's transport with one which counts the requests it sends
+func countRequests(client *gorinth.Client) *countingTransport {
+	counter := &countingTransport{transport: client.HTTPClient.Transport}
+	client.HTTPClient = &http.Client{Transport: counter}
+	return counter
+}
+
+func TestDependenciesAreFetchedInOneRequest(t *testing.T) {
+	server, client := newServer(t)
+	public, _ := server.Project("public")
+	author := server.AddUser(gorinth.User{Username: "author"}, "author-token")
+	library := server.AddProject(gorinth.Project{Slug: "library", Title: "Library"}, author.Id)
+	libraryVersion := server.AddVersion(gorinth.Version{ProjectId: library.Id, Name: "Library", VersionNumber: "2.0.0", VersionType: gorinth.ReleaseRelease}, nil)
+	server.AddVersion(gorinth.Version{
+		ProjectId:     public.Id,
+		Name:          "First release",
+		VersionNumber: "1.0.0",
+		VersionType:   gorinth.ReleaseRelease,
+		Dependencies:  []gorinth.Dependency{{VersionId: &libraryVersion.Id, DependencyType: gorinth.DependencyRequired}},
+	}, nil)
+
+	project := getProject(t, client, "public")
+	counter := countRequests(client)
+
+	dependencies, err := project.GetDependencies()
+	if err != nil {
+		t.Fatalf("GetDependencies failed: %s", err)
+	}
+	if counter.requests != 1 {
+		t.Errorf("GetDependencies sent %d requests, want 1", counter.requests)
+	}
+	if len(dependencies.Projects) != 1 || len(dependencies.Versions) != 1 || len(dependencies.Links) != 0 {
+		t.Fatalf("GetDependencies returned %d projects, %d versions and %d links, want 1, 1 and 0",
+			len(dependencies.Projects), len(dependencies.Versions), len(dependencies.Links))
+	}
+
+	versions, err := project.GetVersions()
+	if err != nil {
+		t.Fatalf("GetVersions failed: %s", err)
+	}
+	dependencies.Link(versions)
+
+	if len(dependencies.Links) != 1 {
+		t.Fatalf("Link produced %d links, want 1", len(dependencies.Links))
+	}
+	link := dependencies.Links[0]
+	if link.Dependent.VersionNumber != "1.0.0" || link.Version == nil || link.Version.Id != libraryVersion.Id ||
+		link.Project == nil || link.Project.Id != library.Id {
+		t.Errorf("Link produced %+v, want version 1.0.0 linked to the library's version and project", link)
+	}
+}
@@ -0,0 +1,111 @@
+package gorinthtest_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gearsdatapacks/gorinth"
+)
+
+// Changes the status of a project with one of the status transition methods
+type statusChange func(project *gorinth.Project, auth string) error
+
+var (
+	submitForReview statusChange = (*gorinth.Project).SubmitForReview
+	archive         statusChange = (*gorinth.Project).Archive
+	unlist          statusChange = (*gorinth.Project).Unlist
+	makePrivate     statusChange = (*gorinth.Project).MakePrivate
+	returnToDraft   statusChange = (*gorinth.Project).ReturnToDraft
+)
+
+func TestLegalStatusChanges(t *testing.T) {
+	tests := []struct {
+		name   string
+		from   gorinth.ProjectStatus
+		change statusChange
+		want   gorinth.ProjectStatus
+	}{
+		{"submit draft", gorinth.ProjectDraft, submitForReview, gorinth.ProjectProcessing},
+		{"resubmit rejected", gorinth.ProjectRejected, submitForReview, gorinth.ProjectProcessing},
+		{"resubmit withheld", gorinth.ProjectWithheld, submitForReview, gorinth.ProjectProcessing},
+		{"archive approved", gorinth.ProjectApproved, archive, gorinth.ProjectArchived},
+		{"unlist approved", gorinth.ProjectApproved, unlist, gorinth.ProjectUnlisted},
+		{"make archived private", gorinth.ProjectArchived, makePrivate, gorinth.ProjectPrivate},
+		{"return private to draft", gorinth.ProjectPrivate, returnToDraft, gorinth.ProjectDraft},
+		{"unlist unlisted", gorinth.ProjectUnlisted, unlist, gorinth.ProjectUnlisted},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			project, _ := projectWithStatus(t, test.from)
+
+			if err := test.change(project, ownerToken); err != nil {
+				t.Fatalf("changing status from %s failed: %s", test.from, err)
+			}
+			if project.Status != test.want {
+				t.Errorf("project has status %s after the change, want %s", project.Status, test.want)
+			}
+		})
+	}
+}
+
+func TestIllegalStatusChangesAreRejectedLocally(t *testing.T) {
+	tests := []struct {
+		name   string
+		from   gorinth.ProjectStatus
+		change statusChange
+	}{
+		{"submit approved", gorinth.ProjectApproved, submitForReview},
+		{"submit archived", gorinth.ProjectArchived, submitForReview},
+		{"archive draft", gorinth.ProjectDraft, archive},
+		{"unlist processing", gorinth.ProjectProcessing, unlist},
+		{"make withheld private", gorinth.ProjectWithheld, makePrivate},
+		{"return rejected to draft", gorinth.ProjectRejected, returnToDraft},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			project, client := projectWithStatus(t, test.from)
+			counter := countRequests(client)
+
+			err := test.change(project, ownerToken)
+			if !errors.Is(err, gorinth.ErrInvalidInput) {
+				t.Errorf("got error %v, want ErrInvalidInput", err)
+			}
+			if counter.requests != 0 {
+				t.Errorf("sent %d requests, want the change to be rejected without any", counter.requests)
+			}
+			if project.Status != test.from {
+				t.Errorf("project has status %s after a rejected change, want %s", project.Status, test.from)
+			}
+		})
+	}
+}
+
+func TestSubmittingWithoutVersionsIsRejected(t *testing.T) {
+	_, client := newServer(t)
+	project := getProject(t, client, "hidden")
+
+	err := project.SubmitForReview(ownerToken)
+	if !errors.Is(err, gorinth.ErrInvalidInput) {
+		t.Errorf("got error %v, want ErrInvalidInput", err)
+	}
+	if project.Status != gorinth.ProjectDraft {
+		t.Errorf("project has status %s after a rejected submission, want draft", project.Status)
+	}
+}
+
+// Returns a project with the given status and one version, and the client it was fetched with
+func projectWithStatus(t *testing.T, status gorinth.ProjectStatus) (*gorinth.Project, *gorinth.Client) {
+	t.Helper()
+
+	server, client := newServer(t)
+	owner, err := client.GetUserFromAuth(ownerToken)
+	if err != nil {
+		t.Fatalf("GetUserFromAuth failed: %s", err)
+	}
+	stored := server.AddProject(gorinth.Project{Slug: "subject", Title: "Subject", Status: status}, owner.Id)
+	server.AddVersion(gorinth.Version{ProjectId: stored.Id, Name: "First release", VersionNumber: "1.0.0", VersionType: gorinth.ReleaseRelease}, nil)
+
+	return getProject(t, client, "subject"), client
+}
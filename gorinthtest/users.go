@@ -0,0 +1,146 @@
+package gorinthtest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gearsdatapacks/gorinth"
+)
+
+// Adds a user to the emulator, who is authorised by the given token.
+// Generates an ID and creation date if they aren't set
+func (s *Server) AddUser(user gorinth.User, token string) gorinth.User {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if user.Id == "" {
+		user.Id = s.newId()
+	}
+	if user.Created == "" {
+		user.Created = now()
+	}
+	if user.Role == "" {
+		user.Role = gorinth.UserDeveloper
+	}
+
+	s.users[user.Id] = &user
+	if token != "" {
+		s.tokens[token] = user.Id
+	}
+
+	return user
+}
+
+func (s *Server) findUser(idOrUsername string) *gorinth.User {
+	if user, ok := s.users[idOrUsername]; ok {
+		return user
+	}
+
+	for _, user := range s.users {
+		if strings.EqualFold(user.Username, idOrUsername) {
+			return user
+		}
+	}
+	return nil
+}
+
+// Returns the fields of a user which are visible to everyone
+func publicUser(user *gorinth.User) map[string]any {
+	fields := toMap(user)
+	for _, private := range []string{
+		"email", "payout_data", "auth_providers", "email_verified", "has_password", "has_totp",
+	} {
+		delete(fields, private)
+	}
+	return fields
+}
+
+func (s *Server) handleUser(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 {
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w)
+			return
+		}
+
+		user := s.requireUser(w, r)
+		if user == nil {
+			return
+		}
+		writeJSON(w, http.StatusOK, user)
+		return
+	}
+
+	user := s.findUser(path[0])
+	if user == nil {
+		notFound(w)
+		return
+	}
+
+	if len(path) == 1 && r.Method == http.MethodGet {
+		writeJSON(w, http.StatusOK, publicUser(user))
+		return
+	}
+
+	if len(path) == 2 && path[1] == "follows" && r.Method == http.MethodGet {
+		s.getFollowedProjects(w, r, user)
+		return
+	}
+
+	notFound(w)
+}
+
+func (s *Server) getFollowedProjects(w http.ResponseWriter, r *http.Request, user *gorinth.User) {
+	current := s.requireUser(w, r)
+	if current == nil {
+		return
+	}
+	if current.Id != user.Id {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "You do not have permission to see the projects this user follows!")
+		return
+	}
+
+	response := []map[string]any{}
+	for _, id := range s.follows[user.Id] {
+		if project := s.projects[id]; project != nil && project.visibleTo(user) {
+			response = append(response, project.toJSON())
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) handleFollow(w http.ResponseWriter, r *http.Request, idOrSlug string) {
+	user := s.requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	project := s.findProject(idOrSlug)
+	if project == nil || !project.visibleTo(user) {
+		notFound(w)
+		return
+	}
+
+	following := contains(s.follows[user.Id], project.project.Id)
+
+	switch r.Method {
+	case http.MethodPost:
+		if following {
+			invalidInput(w, "You are already following this project!")
+			return
+		}
+		s.follows[user.Id] = append(s.follows[user.Id], project.project.Id)
+		project.project.Followers++
+	case http.MethodDelete:
+		if !following {
+			invalidInput(w, "You are not following this project!")
+			return
+		}
+		s.follows[user.Id] = remove(s.follows[user.Id], project.project.Id)
+		project.project.Followers--
+	default:
+		methodNotAllowed(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,713 @@
+package gorinthtest
+
+import (
+	"crypto/sha1"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gearsdatapacks/gorinth"
+)
+
+// A version stored in the emulator
+type storedVersion struct {
+	version gorinth.Version
+	files   []*storedFile
+}
+
+// A file uploaded to the emulator, either as part of a version or as an image
+type storedFile struct {
+	url      string
+	filename string
+	hashes   map[string]string
+	data     []byte
+	primary  bool
+	fileType *gorinth.FileType
+}
+
+// Reports whether the version can be seen by the user, who may be nil
+func (v *storedVersion) visibleTo(project *storedProject, user *gorinth.User) bool {
+	if !project.visibleTo(user) {
+		return false
+	}
+
+	switch v.version.Status {
+	case gorinth.VersionListed, gorinth.VersionArchived, gorinth.VersionUnlisted:
+		return true
+	}
+	return project.isMember(user)
+}
+
+// Converts the version to the JSON returned by the API
+func (v *storedVersion) toJSON() map[string]any {
+	fields := toMap(v.version)
+	delete(fields, "file_parts")
+
+	files := []map[string]any{}
+	for _, file := range v.files {
+		files = append(files, map[string]any{
+			"hashes":    file.hashes,
+			"url":       file.url,
+			"filename":  file.filename,
+			"primary":   file.primary,
+			"size":      len(file.data),
+			"file_type": file.fileType,
+		})
+	}
+	fields["files"] = files
+
+	return fields
+}
+
+// Adds a version to the project with the given ID, along with its files, keyed by filename.
+// The first file in alphabetical order is primary. Generates an ID and publication date
+// if they aren't set, and defaults the status to listed. Panics if the project doesn't exist
+func (s *Server) AddVersion(version gorinth.Version, files map[string][]byte) gorinth.Version {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	project := s.findProject(version.ProjectId)
+	if project == nil {
+		panic("gorinthtest: cannot add a version to unknown project " + version.ProjectId)
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	uploads := []upload{}
+	for _, name := range names {
+		uploads = append(uploads, upload{filename: name, data: files[name]})
+	}
+
+	stored := s.storeVersion(project, version, uploads, "")
+	return stored.version
+}
+
+// Returns the contents of an uploaded file, given its URL
+func (s *Server) File(url string) ([]byte, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	file, ok := s.files[url]
+	if !ok {
+		return nil, false
+	}
+	return file.data, true
+}
+
+// A file uploaded to a version
+type upload struct {
+	filename string
+	data     []byte
+	fileType *gorinth.FileType
+}
+
+func (s *Server) storeVersion(project *storedProject, version gorinth.Version, uploads []upload, primaryFile string) *storedVersion {
+	if version.Id == "" {
+		version.Id = s.newId()
+	}
+	if version.DatePublished == "" {
+		version.DatePublished = now()
+	}
+	if version.Status == "" {
+		version.Status = gorinth.VersionListed
+	}
+	if version.Dependencies == nil {
+		version.Dependencies = []gorinth.Dependency{}
+	}
+	if version.GameVersions == nil {
+		version.GameVersions = []string{}
+	}
+	if version.Loaders == nil {
+		version.Loaders = []string{}
+	}
+	version.ProjectId = project.project.Id
+	version.FileParts = nil
+
+	stored := &storedVersion{version: version}
+	for i, upload := range uploads {
+		file := s.storeFile(path.Join(project.project.Id, "versions", version.Id, upload.filename), upload.data)
+		file.fileType = upload.fileType
+		file.primary = upload.filename == primaryFile || (primaryFile == "" && i == 0)
+		stored.files = append(stored.files, file)
+	}
+	s.versions[version.Id] = stored
+
+	project.project.Versions = append(project.project.Versions, version.Id)
+	project.project.GameVersions = union(project.project.GameVersions, version.GameVersions)
+	project.project.Loaders = union(project.project.Loaders, version.Loaders)
+	project.project.Updated = version.DatePublished
+
+	return stored
+}
+
+// Removes a version and its files, leaving the project's list of versions untouched
+func (s *Server) removeVersion(id string) {
+	version := s.versions[id]
+	if version == nil {
+		return
+	}
+
+	for _, file := range version.files {
+		delete(s.files, file.url)
+	}
+	delete(s.versions, id)
+}
+
+// Stores a file, making it available to download at a URL
+func (s *Server) storeFile(filePath string, data []byte) *storedFile {
+	sha1Hash := sha1.Sum(data)
+	sha512Hash := sha512.Sum512(data)
+
+	file := &storedFile{
+		url:      s.URL + "/data/" + filePath,
+		filename: path.Base(filePath),
+		data:     data,
+		hashes: map[string]string{
+			"sha1":   hex.EncodeToString(sha1Hash[:]),
+			"sha512": hex.EncodeToString(sha512Hash[:]),
+		},
+	}
+	s.files[file.url] = file
+
+	return file
+}
+
+// Returns the versions of a project which the user can see, newest first
+func (s *Server) projectVersions(project *storedProject, user *gorinth.User) []*storedVersion {
+	versions := []*storedVersion{}
+	for i := len(project.project.Versions) - 1; i >= 0; i-- {
+		version := s.versions[project.project.Versions[i]]
+		if version != nil && version.visibleTo(project, user) {
+			versions = append(versions, version)
+		}
+	}
+	return versions
+}
+
+func (s *Server) getProjectVersions(w http.ResponseWriter, r *http.Request, idOrSlug string) {
+	project, user := s.visibleProject(w, r, idOrSlug)
+	if project == nil {
+		return
+	}
+
+	query := r.URL.Query()
+	filters := map[string][]string{}
+	for _, key := range []string{"loaders", "game_versions"} {
+		if value := query.Get(key); value != "" {
+			values := []string{}
+			if err := json.Unmarshal([]byte(value), &values); err != nil {
+				invalidInput(w, "%s: %s", key, err)
+				return
+			}
+			filters[key] = values
+		}
+	}
+
+	var featured *bool
+	if value := query.Get("featured"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			invalidInput(w, "featured: %s", err)
+			return
+		}
+		featured = &parsed
+	}
+	versionType := gorinth.ReleaseType(query.Get("version_type"))
+
+	response := []map[string]any{}
+	for _, stored := range s.projectVersions(project, user) {
+		version := stored.version
+		if loaders, ok := filters["loaders"]; ok && !containsAny(version.Loaders, loaders) {
+			continue
+		}
+		if gameVersions, ok := filters["game_versions"]; ok && !containsAny(version.GameVersions, gameVersions) {
+			continue
+		}
+		if featured != nil && version.Featured != *featured {
+			continue
+		}
+		if versionType != "" && version.VersionType != versionType {
+			continue
+		}
+		response = append(response, stored.toJSON())
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) getDependencies(w http.ResponseWriter, r *http.Request, idOrSlug string) {
+	project, user := s.visibleProject(w, r, idOrSlug)
+	if project == nil {
+		return
+	}
+
+	projects := []map[string]any{}
+	versions := []map[string]any{}
+	seen := map[string]bool{}
+
+	addProject := func(id string) {
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+
+		dependencyProject := s.findProject(id)
+		if dependencyProject != nil && dependencyProject.visibleTo(user) {
+			projects = append(projects, dependencyProject.toJSON())
+		}
+	}
+
+	for _, version := range s.projectVersions(project, user) {
+		for _, dependency := range version.version.Dependencies {
+			// Versions that are depended on also include their projects
+			if dependency.VersionId != nil && !seen[*dependency.VersionId] {
+				seen[*dependency.VersionId] = true
+
+				dependencyVersion := s.versions[*dependency.VersionId]
+				if dependencyVersion != nil {
+					dependencyProject := s.projects[dependencyVersion.version.ProjectId]
+					if dependencyProject != nil && dependencyVersion.visibleTo(dependencyProject, user) {
+						versions = append(versions, dependencyVersion.toJSON())
+						addProject(dependencyProject.project.Id)
+					}
+				}
+			}
+
+			if dependency.ProjectId != nil {
+				addProject(*dependency.ProjectId)
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"projects": projects,
+		"versions": versions,
+	})
+}
+
+func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request, path []string) {
+	switch {
+	case len(path) == 0 && r.Method == http.MethodPost:
+		s.createVersion(w, r)
+	case len(path) == 1 && r.Method == http.MethodGet:
+		s.getVersion(w, r, path[0])
+	case len(path) == 1 && r.Method == http.MethodPatch:
+		s.modifyVersion(w, r, path[0])
+	case len(path) == 1 && r.Method == http.MethodDelete:
+		s.deleteVersion(w, r, path[0])
+	case len(path) == 2 && path[1] == "file" && r.Method == http.MethodPost:
+		s.addFiles(w, r, path[0])
+	case len(path) <= 1:
+		methodNotAllowed(w)
+	default:
+		writeError(w, http.StatusNotFound, "not_found", "the requested route does not exist")
+	}
+}
+
+// Finds a version which is visible to the user making the request,
+// writing an error response and returning nil if there isn't one
+func (s *Server) visibleVersion(w http.ResponseWriter, r *http.Request, id string) (*storedVersion, *storedProject, *gorinth.User) {
+	user, ok := s.currentUser(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Authentication Error: Invalid Authentication Credentials")
+		return nil, nil, nil
+	}
+
+	version := s.versions[id]
+	if version == nil {
+		notFound(w)
+		return nil, nil, nil
+	}
+
+	project := s.projects[version.version.ProjectId]
+	if project == nil || !version.visibleTo(project, user) {
+		notFound(w)
+		return nil, nil, nil
+	}
+	return version, project, user
+}
+
+func (s *Server) getVersion(w http.ResponseWriter, r *http.Request, id string) {
+	version, _, _ := s.visibleVersion(w, r, id)
+	if version == nil {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, version.toJSON())
+}
+
+// Finds a version which the user making the request can edit,
+// writing an error response and returning nil if there isn't one
+func (s *Server) editableVersion(w http.ResponseWriter, r *http.Request, id string) (*storedVersion, *storedProject) {
+	version, project, user := s.visibleVersion(w, r, id)
+	if version == nil {
+		return nil, nil
+	}
+
+	if !project.isMember(user) {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "You do not have the permissions to edit this version!")
+		return nil, nil
+	}
+	return version, project
+}
+
+var editableVersionFields = map[string]bool{
+	"name": true, "version_number": true, "changelog": true, "version_type": true,
+	"dependencies": true, "game_versions": true, "loaders": true, "featured": true, "status": true,
+}
+
+func (s *Server) modifyVersion(w http.ResponseWriter, r *http.Request, id string) {
+	stored, _ := s.editableVersion(w, r, id)
+	if stored == nil {
+		return
+	}
+
+	changes := map[string]json.RawMessage{}
+	if err := json.NewDecoder(r.Body).Decode(&changes); err != nil {
+		invalidInput(w, "invalid JSON body: %s", err)
+		return
+	}
+
+	fields := map[string]any{}
+	for key, value := range changes {
+		if editableVersionFields[key] {
+			fields[key] = value
+		}
+	}
+
+	version := stored.version
+	if err := applyFields(&version, fields); err != nil {
+		invalidInput(w, "%s", err)
+		return
+	}
+
+	if version.Name == "" || len(version.Name) > 64 {
+		invalidInput(w, "name: must be between 1 and 64 characters")
+		return
+	}
+	if version.VersionNumber == "" {
+		invalidInput(w, "version_number: must be between 1 and 32 characters")
+		return
+	}
+	switch version.VersionType {
+	case gorinth.ReleaseRelease, gorinth.ReleaseBeta, gorinth.ReleaseAlpha:
+	default:
+		invalidInput(w, "version_type: unknown variant %q", version.VersionType)
+		return
+	}
+	switch version.Status {
+	case gorinth.VersionListed, gorinth.VersionArchived, gorinth.VersionDraft, gorinth.VersionUnlisted:
+	default:
+		invalidInput(w, "status: unknown variant %q", version.Status)
+		return
+	}
+
+	primary := -1
+	if raw, ok := changes["primary_file"]; ok {
+		hash := []string{}
+		if err := json.Unmarshal(raw, &hash); err != nil || len(hash) != 2 {
+			invalidInput(w, "primary_file: expected a hash algorithm and a hash")
+			return
+		}
+
+		for i, file := range stored.files {
+			if file.hashes[hash[0]] == hash[1] {
+				primary = i
+			}
+		}
+		if primary == -1 {
+			invalidInput(w, "Specified file with hash %s does not exist.", hash[1])
+			return
+		}
+	}
+
+	if primary != -1 {
+		for i, file := range stored.files {
+			file.primary = i == primary
+		}
+	}
+	stored.version = version
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) deleteVersion(w http.ResponseWriter, r *http.Request, id string) {
+	stored, project := s.editableVersion(w, r, id)
+	if stored == nil {
+		return
+	}
+
+	project.project.Versions = remove(project.project.Versions, id)
+	s.removeVersion(id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) addFiles(w http.ResponseWriter, r *http.Request, id string) {
+	stored, project := s.editableVersion(w, r, id)
+	if stored == nil {
+		return
+	}
+
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		invalidInput(w, "invalid multipart body: %s", err)
+		return
+	}
+
+	data := struct {
+		FileTypes map[string]*gorinth.FileType `json:"file_types"`
+	}{}
+	if err := json.Unmarshal([]byte(r.FormValue("data")), &data); err != nil {
+		invalidInput(w, "invalid file data: %s", err)
+		return
+	}
+
+	// Every file part is uploaded, since there's no list of file parts when adding files
+	fileParts := []string{}
+	for key := range r.MultipartForm.File {
+		fileParts = append(fileParts, key)
+	}
+	sort.Strings(fileParts)
+
+	uploads, ok := readUploads(w, r, fileParts, data.FileTypes)
+	if !ok {
+		return
+	}
+	if len(uploads) == 0 {
+		invalidInput(w, "At least one file must be specified")
+		return
+	}
+
+	for _, upload := range uploads {
+		for _, file := range stored.files {
+			if file.filename == upload.filename {
+				invalidInput(w, "Duplicate files are not allowed to be uploaded to Modrinth!")
+				return
+			}
+		}
+	}
+
+	for _, upload := range uploads {
+		file := s.storeFile(path.Join(project.project.Id, "versions", id, upload.filename), upload.data)
+		file.fileType = upload.fileType
+		stored.files = append(stored.files, file)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// The data part of a request to create a version
+type createVersionData struct {
+	gorinth.Version
+	PrimaryFile string                       `json:"primary_file"`
+	FileTypes   map[string]*gorinth.FileType `json:"file_types"`
+}
+
+func (s *Server) createVersion(w http.ResponseWriter, r *http.Request) {
+	user := s.requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		invalidInput(w, "invalid multipart body: %s", err)
+		return
+	}
+
+	data := createVersionData{}
+	if err := json.Unmarshal([]byte(r.FormValue("data")), &data); err != nil {
+		invalidInput(w, "invalid version data: %s", err)
+		return
+	}
+
+	project := s.findProject(data.ProjectId)
+	if project == nil || !project.visibleTo(user) {
+		invalidInput(w, "An invalid project id was supplied")
+		return
+	}
+	if !project.isMember(user) {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "You don't have permission to upload this version!")
+		return
+	}
+
+	if data.Name == "" || len(data.Name) > 64 {
+		invalidInput(w, "name: must be between 1 and 64 characters")
+		return
+	}
+	if data.VersionNumber == "" {
+		invalidInput(w, "version_number: must be between 1 and 32 characters")
+		return
+	}
+	switch data.VersionType {
+	case gorinth.ReleaseRelease, gorinth.ReleaseBeta, gorinth.ReleaseAlpha:
+	default:
+		invalidInput(w, "version_type: unknown variant %q", data.VersionType)
+		return
+	}
+
+	uploads, ok := readUploads(w, r, data.FileParts, data.FileTypes)
+	if !ok {
+		return
+	}
+	if len(uploads) == 0 {
+		invalidInput(w, "At least one file must be specified")
+		return
+	}
+
+	primaryFile := ""
+	for _, upload := range uploads {
+		if upload.filename == data.PrimaryFile {
+			primaryFile = upload.filename
+		}
+	}
+
+	version := data.Version
+	version.Id = ""
+	version.DatePublished = ""
+	version.Downloads = 0
+	version.Files = nil
+	version.AuthorId = user.Id
+
+	stored := s.storeVersion(project, version, uploads, primaryFile)
+	writeJSON(w, http.StatusOK, stored.toJSON())
+}
+
+// Reads the files uploaded in a multipart request, each of which must be listed in the file parts.
+// Writes an error response and reports false if the files are invalid
+func readUploads(w http.ResponseWriter, r *http.Request, fileParts []string, fileTypes map[string]*gorinth.FileType) ([]upload, bool) {
+	uploads := []upload{}
+	seen := map[string]bool{}
+
+	for _, part := range fileParts {
+		files := r.MultipartForm.File[part]
+		if len(files) == 0 {
+			continue
+		}
+
+		file, err := files[0].Open()
+		if err != nil {
+			invalidInput(w, "%s", err)
+			return nil, false
+		}
+		data, err := io.ReadAll(file)
+		file.Close()
+		if err != nil {
+			invalidInput(w, "%s", err)
+			return nil, false
+		}
+
+		filename := path.Base(strings.ReplaceAll(files[0].Filename, "\\", "/"))
+		if seen[filename] {
+			invalidInput(w, "Duplicate files are not allowed to be uploaded to Modrinth!")
+			return nil, false
+		}
+		seen[filename] = true
+
+		fileType := fileTypes[part]
+		if fileType == nil {
+			fileType = fileTypes[filename]
+		}
+		uploads = append(uploads, upload{filename: filename, data: data, fileType: fileType})
+	}
+
+	for key := range r.MultipartForm.File {
+		if !contains(fileParts, key) {
+			invalidInput(w, "File `%s` (field %s) isn't specified in the versions data", key, key)
+			return nil, false
+		}
+	}
+
+	return uploads, true
+}
+
+func (s *Server) handleVersionFile(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) != 1 || r.Method != http.MethodGet {
+		writeError(w, http.StatusNotFound, "not_found", "the requested route does not exist")
+		return
+	}
+
+	algorithm := r.URL.Query().Get("algorithm")
+	if algorithm == "" {
+		algorithm = "sha1"
+	}
+
+	user, ok := s.currentUser(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Authentication Error: Invalid Authentication Credentials")
+		return
+	}
+
+	for _, version := range s.versions {
+		project := s.projects[version.version.ProjectId]
+		if project == nil || !version.visibleTo(project, user) {
+			continue
+		}
+
+		for _, file := range version.files {
+			if file.hashes[algorithm] == path[0] {
+				writeJSON(w, http.StatusOK, version.toJSON())
+				return
+			}
+		}
+	}
+
+	notFound(w)
+}
+
+func (s *Server) handleData(w http.ResponseWriter, r *http.Request, path []string) {
+	file, ok := s.files[s.URL+r.URL.Path]
+	if !ok || r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", http.DetectContentType(file.data))
+	w.Write(file.data)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Reports whether any of the wanted values are in the list
+func containsAny(values []string, wanted []string) bool {
+	for _, want := range wanted {
+		if contains(values, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the list without any occurrences of the value
+func remove(values []string, value string) []string {
+	result := []string{}
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// Returns the values of both lists, without duplicates
+func union(a []string, b []string) []string {
+	result := []string{}
+	for _, value := range append(append([]string{}, a...), b...) {
+		if !contains(result, value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
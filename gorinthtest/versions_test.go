@@ -0,0 +1,183 @@
+package gorinthtest_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gearsdatapacks/gorinth"
+)
+
+// Adds a library version and a version of the public project which depends on it,
+// with the given status, returning the library version fetched with the client
+func versionWithDependent(t *testing.T, status gorinth.VersionStatus) (*gorinth.Version, *gorinth.Client) {
+	t.Helper()
+
+	server, client := newServer(t)
+	public, _ := server.Project("public")
+	library := server.AddVersion(gorinth.Version{ProjectId: public.Id, Name: "Library", VersionNumber: "1.0.0", VersionType: gorinth.ReleaseRelease}, nil)
+	server.AddVersion(gorinth.Version{
+		ProjectId:     public.Id,
+		Name:          "Addon",
+		VersionNumber: "1.1.0",
+		VersionType:   gorinth.ReleaseRelease,
+		Status:        status,
+		Dependencies:  []gorinth.Dependency{{VersionId: &library.Id, DependencyType: gorinth.DependencyRequired}},
+	}, nil)
+
+	version, err := client.GetVersion(library.Id, ownerToken)
+	if err != nil {
+		t.Fatalf("GetVersion failed: %s", err)
+	}
+	return version, client
+}
+
+func TestDeletingDependedOnVersionIsRefused(t *testing.T) {
+	version, client := versionWithDependent(t, gorinth.VersionListed)
+	counter := countRequests(client)
+
+	err := version.Delete(ownerToken)
+	if !errors.Is(err, gorinth.ErrSafetyCheck) {
+		t.Errorf("got error %v, want ErrSafetyCheck", err)
+	}
+	if deletes := counter.methods[http.MethodDelete]; deletes != 0 {
+		t.Errorf("sent %d DELETE requests before refusing", deletes)
+	}
+
+	if _, err := client.GetVersion(version.Id, ownerToken); err != nil {
+		t.Errorf("GetVersion of the refused version failed: %s", err)
+	}
+}
+
+func TestDeletingVersionWithoutPublicDependentsSucceeds(t *testing.T) {
+	tests := []struct {
+		name   string
+		status gorinth.VersionStatus
+		opts   []gorinth.Option
+	}{
+		{"draft dependent", gorinth.VersionDraft, nil},
+		{"forced", gorinth.VersionListed, []gorinth.Option{gorinth.Force()}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			version, client := versionWithDependent(t, test.status)
+
+			if err := version.Delete(ownerToken, test.opts...); err != nil {
+				t.Fatalf("Delete failed: %s", err)
+			}
+			if _, err := client.GetVersion(version.Id, ownerToken); !errors.Is(err, gorinth.ErrNotFound) {
+				t.Errorf("GetVersion of the deleted version returned %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestQueryVersionsFiltersVersions(t *testing.T) {
+	server, client := newServer(t)
+	public, _ := server.Project("public")
+	versions := []gorinth.Version{
+		{VersionNumber: "1.0.0", Loaders: []string{"fabric"}, GameVersions: []string{"1.20.1"}, Featured: true},
+		{VersionNumber: "1.1.0", Loaders: []string{"forge"}, GameVersions: []string{"1.20.1"}, Featured: true},
+		{VersionNumber: "1.2.0", Loaders: []string{"fabric"}, GameVersions: []string{"1.19.4"}, Featured: true},
+		{VersionNumber: "1.3.0", Loaders: []string{"fabric"}, GameVersions: []string{"1.20.1"}, Featured: false},
+		{VersionNumber: "1.4.0", Loaders: []string{"fabric"}, GameVersions: []string{"1.20.1"}, Featured: true, Status: gorinth.VersionArchived},
+	}
+	for _, version := range versions {
+		version.ProjectId = public.Id
+		version.Name = "Version " + version.VersionNumber
+		version.VersionType = gorinth.ReleaseRelease
+		server.AddVersion(version, nil)
+	}
+	project := getProject(t, client, "public")
+
+	featured := true
+	tests := []struct {
+		name  string
+		query gorinth.VersionQuery
+		want  []string
+	}{
+		{"loaders", gorinth.VersionQuery{Loaders: []string{"fabric"}}, []string{"1.0.0", "1.2.0", "1.3.0", "1.4.0"}},
+		{"game versions", gorinth.VersionQuery{GameVersions: []string{"1.20.1"}}, []string{"1.0.0", "1.1.0", "1.3.0", "1.4.0"}},
+		{"featured", gorinth.VersionQuery{Featured: &featured}, []string{"1.0.0", "1.1.0", "1.2.0", "1.4.0"}},
+		{"status", gorinth.VersionQuery{Status: gorinth.VersionListed}, []string{"1.0.0", "1.1.0", "1.2.0", "1.3.0"}},
+		{"everything", gorinth.VersionQuery{
+			Loaders:      []string{"fabric"},
+			GameVersions: []string{"1.20.1"},
+			Featured:     &featured,
+			VersionType:  gorinth.ReleaseRelease,
+			Status:       gorinth.VersionListed,
+		}, []string{"1.0.0"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matching, err := project.QueryVersions(test.query)
+			if err != nil {
+				t.Fatalf("QueryVersions failed: %s", err)
+			}
+
+			numbers := []string{}
+			for _, version := range matching {
+				numbers = append(numbers, version.VersionNumber)
+			}
+			sort.Strings(numbers)
+			if strings.Join(numbers, " ") != strings.Join(test.want, " ") {
+				t.Errorf("QueryVersions returned versions %v, want %v", numbers, test.want)
+			}
+		})
+	}
+}
+
+func versionNumbers(t *testing.T, project *gorinth.Project) []string {
+	t.Helper()
+
+	versions, err := project.GetVersions()
+	if err != nil {
+		t.Fatalf("GetVersions failed: %s", err)
+	}
+
+	numbers := []string{}
+	for _, version := range versions {
+		numbers = append(numbers, version.VersionNumber)
+	}
+	return numbers
+}
+
+func TestPublishedVersionIsListed(t *testing.T) {
+	_, client := newServer(t)
+	project := getProject(t, client, "public")
+
+	version, err := project.PublishVersion(gorinth.NewVersion{
+		Version: gorinth.Version{
+			Name:          "First release",
+			VersionNumber: "1.0.0",
+			VersionType:   gorinth.ReleaseRelease,
+			Loaders:       []string{"fabric"},
+			GameVersions:  []string{"1.20.1"},
+		},
+		Files: []gorinth.FileUpload{{Name: "mod.jar", Data: bytes.NewReader([]byte("jar"))}},
+	}, ownerToken)
+	if err != nil {
+		t.Fatalf("PublishVersion failed: %s", err)
+	}
+	if version.Id == "" || len(version.Files) != 1 || version.Files[0].Url == "" {
+		t.Errorf("PublishVersion returned %+v, want a version with an ID and a file URL", version)
+	}
+
+	numbers := versionNumbers(t, project)
+	if len(numbers) != 1 || numbers[0] != "1.0.0" {
+		t.Errorf("GetVersions returned versions %v, want [1.0.0]", numbers)
+	}
+
+	fetched, err := client.GetVersion(version.Id, "")
+	if err != nil {
+		t.Fatalf("GetVersion failed: %s", err)
+	}
+	if fetched.Name != "First release" || fetched.Files[0].Filename != "mod.jar" {
+		t.Errorf("GetVersion returned %+v, want the published version", fetched)
+	}
+}
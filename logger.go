@@ -0,0 +1,43 @@
+package gorinth
+
+import (
+	"net/http"
+)
+
+// Receives log messages from a client. The arguments after a message are
+// alternating keys and values, so a *slog.Logger can be used directly
+type Logger interface {
+	Debug(message string, args ...any)
+	Info(message string, args ...any)
+	Warn(message string, args ...any)
+	Error(message string, args ...any)
+}
+
+// A logger which discards all messages, used by clients which don't set one
+type nopLogger struct{}
+
+func (nopLogger) Debug(message string, args ...any) {}
+func (nopLogger) Info(message string, args ...any)  {}
+func (nopLogger) Warn(message string, args ...any)  {}
+func (nopLogger) Error(message string, args ...any) {}
+
+func (c *Client) logger() Logger {
+	if c.Logger == nil {
+		return nopLogger{}
+	}
+	return c.Logger
+}
+
+// Headers whose values are never logged
+var redactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// Returns a copy of the headers which is safe to log, with tokens redacted
+func redactHeaders(header http.Header) http.Header {
+	redacted := header.Clone()
+	for _, key := range redactedHeaders {
+		if redacted.Get(key) != "" {
+			redacted.Set(key, "[REDACTED]")
+		}
+	}
+	return redacted
+}
@@ -70,8 +70,9 @@ type Project struct {
 	Gallery []GalleryImage `json:"gallery"`
 	// Separate from the project in the Modrinth API,
 	// stores the image data for the project's icon
-	Icon []byte
-	auth string
+	Icon   []byte
+	auth   string
+	client *Client
 }
 
 // Represents one search result when searching. Pointer fields are optional
@@ -159,6 +160,7 @@ type Version struct {
 	// The files for this version
 	Files     []VersionFile `json:"files"`
 	FileParts []string      `json:"file_parts"`
+	client    *Client
 }
 
 // Represents a Modrinth user. Pointer fields are optional
@@ -196,4 +198,5 @@ type User struct {
 	// Only present if requesting your own account
 	HasTOTP bool `json:"has_totp"`
 	auth    string
+	client  *Client
 }
@@ -0,0 +1,35 @@
+package gorinth
+
+// Changes the behaviour of an API call which accepts options
+type Option func(*options)
+
+type options struct {
+	// Whether to check that a project's slug is available before creating it
+	checkSlug bool
+	// Whether to skip the safety checks of destructive operations
+	force bool
+}
+
+func collectOptions(opts []Option) options {
+	result := options{}
+	for _, opt := range opts {
+		opt(&result)
+	}
+	return result
+}
+
+// Checks that the slug of a project is available before creating it,
+// failing with ErrSlugTaken without creating the project if it isn't
+func CheckSlug() Option {
+	return func(o *options) {
+		o.checkSlug = true
+	}
+}
+
+// Skips the safety checks of destructive operations, such as refusing to delete
+// projects which have been downloaded
+func Force() Option {
+	return func(o *options) {
+		o.force = true
+	}
+}
@@ -2,8 +2,10 @@ package gorinth
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 )
 
@@ -105,3 +107,84 @@ type SearchResponse struct {
 	// The total number of results which matched the query
 	TotalHits int `json:"total_hits"`
 }
+
+// The parameters for filtering the versions of a project.
+// Versions must match every field that is set
+type VersionQuery struct {
+	// Only include versions which support at least one of these loaders
+	Loaders []string
+	// Only include versions which support at least one of these game versions
+	GameVersions []string
+	// Only include versions which are, or aren't, featured
+	Featured *bool
+	// Only include versions with this release type
+	VersionType ReleaseType
+	// Only include versions with this status. Filtered locally, since Modrinth doesn't support it
+	Status VersionStatus
+}
+
+func (q *VersionQuery) toQueryString() (string, error) {
+	parameters := url.Values{}
+
+	if len(q.Loaders) != 0 {
+		loaders, err := json.Marshal(q.Loaders)
+		if err != nil {
+			return "", err
+		}
+		parameters.Set("loaders", string(loaders))
+	}
+
+	if len(q.GameVersions) != 0 {
+		gameVersions, err := json.Marshal(q.GameVersions)
+		if err != nil {
+			return "", err
+		}
+		parameters.Set("game_versions", string(gameVersions))
+	}
+
+	if q.Featured != nil {
+		parameters.Set("featured", strconv.FormatBool(*q.Featured))
+	}
+
+	if len(q.VersionType) != 0 {
+		parameters.Set("version_type", string(q.VersionType))
+	}
+
+	return parameters.Encode(), nil
+}
+
+// Reports whether the version matches every field of the query
+func (q *VersionQuery) matches(version *Version) bool {
+	if len(q.Loaders) != 0 && !containsAny(version.Loaders, q.Loaders) {
+		return false
+	}
+
+	if len(q.GameVersions) != 0 && !containsAny(version.GameVersions, q.GameVersions) {
+		return false
+	}
+
+	if q.Featured != nil && version.Featured != *q.Featured {
+		return false
+	}
+
+	if len(q.VersionType) != 0 && version.VersionType != q.VersionType {
+		return false
+	}
+
+	if len(q.Status) != 0 && version.Status != q.Status {
+		return false
+	}
+
+	return true
+}
+
+func containsAny(values []string, wanted []string) bool {
+	for _, value := range values {
+		for _, want := range wanted {
+			if value == want {
+				return true
+			}
+		}
+	}
+	return false
+}
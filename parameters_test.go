@@ -0,0 +1,40 @@
+package gorinth
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestQueryVersionsFiltersLocally(t *testing.T) {
+	// A server which ignores the query, so every filter has to be applied by the client
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"id": "A", "version_number": "1.0.0", "loaders": ["fabric"], "game_versions": ["1.20.1"], "featured": true, "version_type": "release", "status": "listed"},
+			{"id": "B", "version_number": "1.1.0", "loaders": ["forge"], "game_versions": ["1.20.1"], "featured": true, "version_type": "release", "status": "listed"},
+			{"id": "C", "version_number": "1.2.0", "loaders": ["fabric"], "game_versions": ["1.19.4"], "featured": true, "version_type": "release", "status": "listed"},
+			{"id": "D", "version_number": "1.3.0", "loaders": ["fabric"], "game_versions": ["1.20.1"], "featured": false, "version_type": "release", "status": "listed"},
+			{"id": "E", "version_number": "1.4.0", "loaders": ["fabric"], "game_versions": ["1.20.1"], "featured": true, "version_type": "beta", "status": "listed"},
+			{"id": "F", "version_number": "1.5.0", "loaders": ["fabric"], "game_versions": ["1.20.1"], "featured": true, "version_type": "release", "status": "draft"}
+		]`))
+	})
+	project := &Project{Id: "AABBCCDD", Slug: "test", client: client}
+
+	featured := true
+	versions, err := project.QueryVersions(VersionQuery{
+		Loaders:      []string{"fabric", "quilt"},
+		GameVersions: []string{"1.20.1"},
+		Featured:     &featured,
+		VersionType:  ReleaseRelease,
+		Status:       VersionListed,
+	})
+	if err != nil {
+		t.Fatalf("QueryVersions failed: %s", err)
+	}
+
+	if len(versions) != 1 || versions[0].Id != "A" {
+		t.Errorf("QueryVersions returned %+v, want only version A", versions)
+	}
+	if len(versions) != 0 && versions[0].client != client {
+		t.Error("QueryVersions didn't attach the client to the returned version")
+	}
+}
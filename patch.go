@@ -0,0 +1,243 @@
+package gorinth
+
+// A set of changes to make to a project with Project.Patch.
+// Unlike Modify, which can only set fields, a patch can also clear them.
+// Only the fields which are set or cleared are sent, and the zero value changes nothing
+type ProjectPatch struct {
+	fields map[string]any
+}
+
+func (patch *ProjectPatch) set(key string, value any) *ProjectPatch {
+	if patch.fields == nil {
+		patch.fields = map[string]any{}
+	}
+	patch.fields[key] = value
+	return patch
+}
+
+// Reports whether the patch changes nothing
+func (patch *ProjectPatch) Empty() bool {
+	return patch == nil || len(patch.fields) == 0
+}
+
+// Sets the slug of the project
+func (patch *ProjectPatch) SetSlug(slug string) *ProjectPatch {
+	return patch.set("slug", slug)
+}
+
+// Sets the title of the project
+func (patch *ProjectPatch) SetTitle(title string) *ProjectPatch {
+	return patch.set("title", title)
+}
+
+// Sets the short description of the project
+func (patch *ProjectPatch) SetDescription(description string) *ProjectPatch {
+	return patch.set("description", description)
+}
+
+// Sets the long form description of the project
+func (patch *ProjectPatch) SetBody(body string) *ProjectPatch {
+	return patch.set("body", body)
+}
+
+// Sets the categories that the project is listed under
+func (patch *ProjectPatch) SetCategories(categories []string) *ProjectPatch {
+	if categories == nil {
+		categories = []string{}
+	}
+	return patch.set("categories", categories)
+}
+
+// Removes the project from all of its categories
+func (patch *ProjectPatch) ClearCategories() *ProjectPatch {
+	return patch.set("categories", []string{})
+}
+
+// Sets the searchable but non-primary categories of the project
+func (patch *ProjectPatch) SetAdditionalCategories(categories []string) *ProjectPatch {
+	if categories == nil {
+		categories = []string{}
+	}
+	return patch.set("additional_categories", categories)
+}
+
+// Removes all of the project's additional categories
+func (patch *ProjectPatch) ClearAdditionalCategories() *ProjectPatch {
+	return patch.set("additional_categories", []string{})
+}
+
+// Sets the client side support of the project
+func (patch *ProjectPatch) SetClientSide(support Support) *ProjectPatch {
+	return patch.set("client_side", support)
+}
+
+// Sets the server side support of the project
+func (patch *ProjectPatch) SetServerSide(support Support) *ProjectPatch {
+	return patch.set("server_side", support)
+}
+
+// Sets the link to where to submit bugs or issues with the project
+func (patch *ProjectPatch) SetIssuesUrl(url string) *ProjectPatch {
+	return patch.set("issues_url", url)
+}
+
+// Removes the project's issues link
+func (patch *ProjectPatch) ClearIssuesUrl() *ProjectPatch {
+	return patch.set("issues_url", nil)
+}
+
+// Sets the link to the source code of the project
+func (patch *ProjectPatch) SetSourceUrl(url string) *ProjectPatch {
+	return patch.set("source_url", url)
+}
+
+// Removes the project's source code link
+func (patch *ProjectPatch) ClearSourceUrl() *ProjectPatch {
+	return patch.set("source_url", nil)
+}
+
+// Sets the link to the project's wiki page
+func (patch *ProjectPatch) SetWikiUrl(url string) *ProjectPatch {
+	return patch.set("wiki_url", url)
+}
+
+// Removes the project's wiki link
+func (patch *ProjectPatch) ClearWikiUrl() *ProjectPatch {
+	return patch.set("wiki_url", nil)
+}
+
+// Sets the invite link to the project's discord
+func (patch *ProjectPatch) SetDiscordUrl(url string) *ProjectPatch {
+	return patch.set("discord_url", url)
+}
+
+// Removes the project's discord invite link
+func (patch *ProjectPatch) ClearDiscordUrl() *ProjectPatch {
+	return patch.set("discord_url", nil)
+}
+
+// Sets the donation links of the project
+func (patch *ProjectPatch) SetDonationUrls(urls []string) *ProjectPatch {
+	if urls == nil {
+		urls = []string{}
+	}
+	return patch.set("donation_urls", urls)
+}
+
+// Removes all of the project's donation links
+func (patch *ProjectPatch) ClearDonationUrls() *ProjectPatch {
+	return patch.set("donation_urls", []string{})
+}
+
+// Sets the SPDX ID of the project's license
+func (patch *ProjectPatch) SetLicense(id string) *ProjectPatch {
+	return patch.set("license_id", id)
+}
+
+// Sets the link to the project's license
+func (patch *ProjectPatch) SetLicenseUrl(url string) *ProjectPatch {
+	return patch.set("license_url", url)
+}
+
+// Removes the link to the project's license
+func (patch *ProjectPatch) ClearLicenseUrl() *ProjectPatch {
+	return patch.set("license_url", nil)
+}
+
+// Sets the status the project is given once it has been approved
+func (patch *ProjectPatch) SetRequestedStatus(status RequestedProjectStatus) *ProjectPatch {
+	return patch.set("requested_status", status)
+}
+
+// Removes the project's requested status
+func (patch *ProjectPatch) ClearRequestedStatus() *ProjectPatch {
+	return patch.set("requested_status", nil)
+}
+
+// A set of changes to make to a version with Version.Modify.
+// Only the fields which are set or cleared are sent, and the zero value changes nothing
+type VersionPatch struct {
+	fields map[string]any
+}
+
+func (patch *VersionPatch) set(key string, value any) *VersionPatch {
+	if patch.fields == nil {
+		patch.fields = map[string]any{}
+	}
+	patch.fields[key] = value
+	return patch
+}
+
+// Reports whether the patch changes nothing
+func (patch *VersionPatch) Empty() bool {
+	return patch == nil || len(patch.fields) == 0
+}
+
+// Sets the name of the version
+func (patch *VersionPatch) SetName(name string) *VersionPatch {
+	return patch.set("name", name)
+}
+
+// Sets the version number of the version
+func (patch *VersionPatch) SetVersionNumber(versionNumber string) *VersionPatch {
+	return patch.set("version_number", versionNumber)
+}
+
+// Sets the changelog of the version
+func (patch *VersionPatch) SetChangelog(changelog string) *VersionPatch {
+	return patch.set("changelog", changelog)
+}
+
+// Removes the version's changelog
+func (patch *VersionPatch) ClearChangelog() *VersionPatch {
+	return patch.set("changelog", "")
+}
+
+// Sets the specific versions of projects that the version depends on
+func (patch *VersionPatch) SetDependencies(dependencies []Dependency) *VersionPatch {
+	if dependencies == nil {
+		dependencies = []Dependency{}
+	}
+	return patch.set("dependencies", dependencies)
+}
+
+// Removes all of the version's dependencies
+func (patch *VersionPatch) ClearDependencies() *VersionPatch {
+	return patch.set("dependencies", []Dependency{})
+}
+
+// Sets the versions of Minecraft that the version supports
+func (patch *VersionPatch) SetGameVersions(gameVersions []string) *VersionPatch {
+	if gameVersions == nil {
+		gameVersions = []string{}
+	}
+	return patch.set("game_versions", gameVersions)
+}
+
+// Sets the mod loaders that the version supports
+func (patch *VersionPatch) SetLoaders(loaders []string) *VersionPatch {
+	if loaders == nil {
+		loaders = []string{}
+	}
+	return patch.set("loaders", loaders)
+}
+
+// Sets the release type of the version
+func (patch *VersionPatch) SetVersionType(versionType ReleaseType) *VersionPatch {
+	return patch.set("version_type", versionType)
+}
+
+// Sets whether the version is featured
+func (patch *VersionPatch) SetFeatured(featured bool) *VersionPatch {
+	return patch.set("featured", featured)
+}
+
+// Sets the status of the version
+func (patch *VersionPatch) SetStatus(status VersionStatus) *VersionPatch {
+	return patch.set("status", status)
+}
+
+// Sets which of the version's files is its primary file
+func (patch *VersionPatch) SetPrimaryFile(file VersionFile) *VersionPatch {
+	return patch.set("primary_file", []string{"sha1", file.Hashes.Sha1})
+}
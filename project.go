@@ -1,86 +1,367 @@
 package gorinth
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
+	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 )
 
 // Searches Modrinth for projects matching a query
 func SearchProjects(query SearchQuery) (*SearchResponse, error) {
-	url := fmt.Sprintf("https://api.modrinth.com/v2/search?%s", query.toQueryString())
-	body, statusCode, err := get(url, map[string]string{})
+	return DefaultClient.SearchProjects(query)
+}
+
+// Searches Modrinth for projects matching a query, cancelling the request if the context is done
+func SearchProjectsContext(ctx context.Context, query SearchQuery) (*SearchResponse, error) {
+	return DefaultClient.SearchProjectsContext(ctx, query)
+}
+
+// Searches Modrinth for projects matching a query
+func (c *Client) SearchProjects(query SearchQuery) (*SearchResponse, error) {
+	return c.SearchProjectsContext(context.Background(), query)
+}
+
+// Searches Modrinth for projects matching a query, cancelling the request if the context is done
+func (c *Client) SearchProjectsContext(ctx context.Context, query SearchQuery) (*SearchResponse, error) {
+	url := c.endpoint("search") + "?" + query.toQueryString()
+	response, err := c.get(ctx, url, map[string]string{})
 	if err != nil {
 		return nil, err
 	}
 
-	if statusCode == 400 {
-		return nil, makeError("Invalid request when attempting to search projects: %s", string(body))
+	if response.status == 400 {
+		return nil, response.error("Invalid request when attempting to search projects")
 	}
 
-	if statusCode != 200 {
-		return nil, makeError("Unexpected status code when searching projects: %d", statusCode)
+	if response.status != 200 {
+		return nil, response.error("Unexpected status code when searching projects")
 	}
 
-	response := &SearchResponse{}
-	err = json.Unmarshal(body, response)
+	results := &SearchResponse{}
+	err = json.Unmarshal(response.body, results)
 	if err != nil {
 		return nil, err
 	}
-	return response, nil
+	return results, nil
 }
 
 // Returns a Project Model of the project with a matching ID or slug with the one provided.
 func GetProject(id_or_slug string, auth string) (*Project, error) {
-	url := fmt.Sprintf("https://api.modrinth.com/v2/project/%s", id_or_slug)
-	result, statusCode, err := get(url, authHeader(auth))
+	return DefaultClient.GetProject(id_or_slug, auth)
+}
+
+// Returns a Project Model of the project with a matching ID or slug with the one provided,
+// cancelling the request if the context is done
+func GetProjectContext(ctx context.Context, id_or_slug string, auth string) (*Project, error) {
+	return DefaultClient.GetProjectContext(ctx, id_or_slug, auth)
+}
+
+// Returns a Project Model of the project with a matching ID or slug with the one provided.
+func (c *Client) GetProject(id_or_slug string, auth string) (*Project, error) {
+	return c.GetProjectContext(context.Background(), id_or_slug, auth)
+}
+
+// Returns a Project Model of the project with a matching ID or slug with the one provided,
+// cancelling the request if the context is done
+func (c *Client) GetProjectContext(ctx context.Context, id_or_slug string, auth string) (*Project, error) {
+	url := c.endpoint("project", id_or_slug)
+	response, err := c.get(ctx, url, c.authHeader(auth))
 	if err != nil {
 		return nil, err
 	}
 
-	if statusCode == 404 {
-		return nil, makeError("Project %q wasn't found or no authorization to see this project", id_or_slug)
+	if response.status == 404 {
+		return nil, response.error("Project %q wasn't found or no authorization to see this project", id_or_slug)
+	}
+
+	if response.status != 200 {
+		return nil, response.error("Unexpected status code when fetching project %q", id_or_slug)
 	}
 
 	project := Project{}
 
-	err = json.Unmarshal(result, &project)
+	err = json.Unmarshal(response.body, &project)
 	if err != nil {
 		return nil, err
 	}
 
 	project.auth = auth
+	project.client = c
 
 	return &project, nil
 }
 
+// The maximum length of the URLs used to fetch multiple projects at once.
+// Larger requests are split into several, to stay under server URL length limits
+const maxProjectsURLLength = 4096
+
+// Returns the projects with the given IDs or slugs, fetching them in as few requests as possible.
+// The results are keyed by the requested ID or slug, and projects which weren't found are missing
+func GetProjects(ids_or_slugs []string, auth string) (map[string]*Project, error) {
+	return DefaultClient.GetProjects(ids_or_slugs, auth)
+}
+
+// Returns the projects with the given IDs or slugs, fetching them in as few requests as possible,
+// cancelling the requests if the context is done.
+// The results are keyed by the requested ID or slug, and projects which weren't found are missing
+func GetProjectsContext(ctx context.Context, ids_or_slugs []string, auth string) (map[string]*Project, error) {
+	return DefaultClient.GetProjectsContext(ctx, ids_or_slugs, auth)
+}
+
+// Returns the projects with the given IDs or slugs, fetching them in as few requests as possible.
+// The results are keyed by the requested ID or slug, and projects which weren't found are missing
+func (c *Client) GetProjects(ids_or_slugs []string, auth string) (map[string]*Project, error) {
+	return c.GetProjectsContext(context.Background(), ids_or_slugs, auth)
+}
+
+// Returns the projects with the given IDs or slugs, fetching them in as few requests as possible,
+// cancelling the requests if the context is done.
+// The results are keyed by the requested ID or slug, and projects which weren't found are missing
+func (c *Client) GetProjectsContext(ctx context.Context, ids_or_slugs []string, auth string) (map[string]*Project, error) {
+	results := map[string]*Project{}
+
+	for _, chunk := range c.projectsChunks(ids_or_slugs) {
+		ids, err := json.Marshal(chunk)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := c.get(ctx, c.endpoint("projects")+"?ids="+url.QueryEscape(string(ids)), c.authHeader(auth))
+		if err != nil {
+			return nil, err
+		}
+
+		if response.status != 200 {
+			return nil, response.error("Unexpected status code when fetching %d projects", len(chunk))
+		}
+
+		projects := []Project{}
+		err = json.Unmarshal(response.body, &projects)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range projects {
+			project := &projects[i]
+			project.auth = auth
+			project.client = c
+
+			for _, id := range chunk {
+				if project.Id == id || strings.EqualFold(project.Slug, id) {
+					results[id] = project
+				}
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// Splits IDs into groups which can each be fetched in one request without exceeding
+// the maximum URL length, removing any duplicates
+func (c *Client) projectsChunks(ids []string) [][]string {
+	baseLength := len(c.endpoint("projects") + "?ids=" + url.QueryEscape("[]"))
+
+	chunks := [][]string{}
+	chunk := []string{}
+	length := baseLength
+	seen := map[string]bool{}
+
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		// The length of the ID once quoted, separated by a comma and escaped
+		idLength := len(url.QueryEscape(fmt.Sprintf("%q,", id)))
+		if len(chunk) != 0 && length+idLength > maxProjectsURLLength {
+			chunks = append(chunks, chunk)
+			chunk = []string{}
+			length = baseLength
+		}
+
+		chunk = append(chunk, id)
+		length += idLength
+	}
+
+	if len(chunk) != 0 {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// The maximum number of projects which can be requested by GetRandomProjects
+const maxRandomProjects = 100
+
+// Returns the given number of randomly chosen projects, which must be between 1 and 100
+func GetRandomProjects(count int, auth string) ([]Project, error) {
+	return DefaultClient.GetRandomProjects(count, auth)
+}
+
+// Returns the given number of randomly chosen projects, which must be between 1 and 100,
+// cancelling the request if the context is done
+func GetRandomProjectsContext(ctx context.Context, count int, auth string) ([]Project, error) {
+	return DefaultClient.GetRandomProjectsContext(ctx, count, auth)
+}
+
+// Returns the given number of randomly chosen projects, which must be between 1 and 100
+func (c *Client) GetRandomProjects(count int, auth string) ([]Project, error) {
+	return c.GetRandomProjectsContext(context.Background(), count, auth)
+}
+
+// Returns the given number of randomly chosen projects, which must be between 1 and 100,
+// cancelling the request if the context is done
+func (c *Client) GetRandomProjectsContext(ctx context.Context, count int, auth string) ([]Project, error) {
+	if count < 1 || count > maxRandomProjects {
+		return nil, makeValidationError("Invalid number of random projects %d, must be between 1 and %d", count, maxRandomProjects)
+	}
+
+	url := fmt.Sprintf("%s?count=%d", c.endpoint("projects_random"), count)
+	response, err := c.get(ctx, url, c.authHeader(auth))
+	if err != nil {
+		return nil, err
+	}
+
+	if response.status != 200 {
+		return nil, response.error("Unexpected status code when fetching random projects")
+	}
+
+	projects := []Project{}
+	err = json.Unmarshal(response.body, &projects)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range projects {
+		projects[i].auth = auth
+		projects[i].client = c
+	}
+
+	return projects, nil
+}
+
+// Checks whether a project with the given ID or slug exists,
+// returning its ID if it does. Use this to check if a slug is available
+func CheckProject(id_or_slug string, auth string) (id string, exists bool, err error) {
+	return DefaultClient.CheckProject(id_or_slug, auth)
+}
+
+// Checks whether a project with the given ID or slug exists,
+// returning its ID if it does, cancelling the request if the context is done.
+// Use this to check if a slug is available
+func CheckProjectContext(ctx context.Context, id_or_slug string, auth string) (id string, exists bool, err error) {
+	return DefaultClient.CheckProjectContext(ctx, id_or_slug, auth)
+}
+
+// Checks whether a project with the given ID or slug exists,
+// returning its ID if it does. Use this to check if a slug is available
+func (c *Client) CheckProject(id_or_slug string, auth string) (id string, exists bool, err error) {
+	return c.CheckProjectContext(context.Background(), id_or_slug, auth)
+}
+
+// Checks whether a project with the given ID or slug exists,
+// returning its ID if it does, cancelling the request if the context is done.
+// Use this to check if a slug is available
+func (c *Client) CheckProjectContext(ctx context.Context, id_or_slug string, auth string) (id string, exists bool, err error) {
+	response, err := c.get(ctx, c.endpoint("project", id_or_slug, "check"), c.authHeader(auth))
+	if err != nil {
+		return "", false, err
+	}
+
+	if response.status == 404 {
+		return "", false, nil
+	}
+
+	if response.status != 200 {
+		return "", false, response.error("Unexpected status code when checking project %q", id_or_slug)
+	}
+
+	responseSchema := struct {
+		Id string `json:"id"`
+	}{}
+	err = json.Unmarshal(response.body, &responseSchema)
+	if err != nil {
+		return "", false, err
+	}
+
+	return responseSchema.Id, true, nil
+}
+
 // Returns all versions of the project
 func (project *Project) GetVersions() ([]Version, error) {
-	url := fmt.Sprintf("https://api.modrinth.com/v2/project/%s/version", project.Slug)
-	result, statusCode, err := get(url, authHeader(project.auth))
+	return project.GetVersionsContext(context.Background())
+}
+
+// Returns all versions of the project, cancelling the request if the context is done
+func (project *Project) GetVersionsContext(ctx context.Context) ([]Version, error) {
+	return project.QueryVersionsContext(ctx, VersionQuery{})
+}
+
+// Returns the versions of the project which match the query.
+// Modrinth filters the versions where it can, so fewer have to be downloaded
+func (project *Project) QueryVersions(query VersionQuery) ([]Version, error) {
+	return project.QueryVersionsContext(context.Background(), query)
+}
+
+// Returns the versions of the project which match the query,
+// cancelling the request if the context is done
+func (project *Project) QueryVersionsContext(ctx context.Context, query VersionQuery) ([]Version, error) {
+	queryString, err := query.toQueryString()
+	if err != nil {
+		return nil, err
+	}
+
+	c := project.client.orDefault()
+	url := c.endpoint("project", project.Slug, "version")
+	if queryString != "" {
+		url += "?" + queryString
+	}
+	response, err := c.get(ctx, url, c.authHeader(project.auth))
 	if err != nil {
 		return nil, err
 	}
 
-	if statusCode == 404 {
-		return nil, makeError("Project %q wasn't found or no authorization to see this project", project.Slug)
+	if response.status == 404 {
+		return nil, response.error("Project %q wasn't found or no authorization to see this project", project.Slug)
+	}
+
+	if response.status != 200 {
+		return nil, response.error("Unexpected status code when fetching versions of project %q", project.Slug)
 	}
 
-	response := []Version{}
-	err = json.Unmarshal(result, &response)
+	versions := []Version{}
+	err = json.Unmarshal(response.body, &versions)
 	if err != nil {
 		return nil, err
 	}
 
-	return response, nil
+	// Not every field can be filtered by Modrinth, so check the rest here
+	matching := versions[:0]
+	for i := range versions {
+		if query.matches(&versions[i]) {
+			versions[i].attach(c)
+			matching = append(matching, versions[i])
+		}
+	}
+
+	return matching, nil
 }
 
 // Returns the most recently created version of the project
 func (project *Project) GetLatestVersion() (*Version, error) {
-	versions, err := project.GetVersions()
+	return project.GetLatestVersionContext(context.Background())
+}
+
+// Returns the most recently created version of the project, cancelling the request if the context is done
+func (project *Project) GetLatestVersionContext(ctx context.Context) (*Version, error) {
+	versions, err := project.GetVersionsContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -94,7 +375,13 @@ func (project *Project) GetLatestVersion() (*Version, error) {
 
 // Returns the version of the project whose semver string matches the given string
 func (project *Project) GetSpecificVersion(versionNumber string) (*Version, error) {
-	versions, err := project.GetVersions()
+	return project.GetSpecificVersionContext(context.Background(), versionNumber)
+}
+
+// Returns the version of the project whose semver string matches the given string,
+// cancelling the request if the context is done
+func (project *Project) GetSpecificVersionContext(ctx context.Context, versionNumber string) (*Version, error) {
+	versions, err := project.GetVersionsContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -108,107 +395,271 @@ func (project *Project) GetSpecificVersion(versionNumber string) (*Version, erro
 	return nil, makeError("Cannot find version %s of project %q", versionNumber, project.Title)
 }
 
-// Creates a version associated with the project
+// Creates a version associated with the project, uploading the files at the paths in its FileParts
 func (project *Project) CreateVersion(version Version, auth string) error {
-	if version.Status == "" {
-		version.Status = "listed"
+	return project.CreateVersionContext(context.Background(), version, auth)
+}
+
+// Creates a version associated with the project, uploading the files at the paths in its FileParts,
+// cancelling the request if the context is done
+func (project *Project) CreateVersionContext(ctx context.Context, version Version, auth string) error {
+	files := make([]FileUpload, 0, len(version.FileParts))
+	for _, path := range version.FileParts {
+		fileReader, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer fileReader.Close()
+
+		files = append(files, FileUpload{Name: filepath.Base(path), Data: fileReader})
+	}
+
+	_, err := project.PublishVersionContext(ctx, NewVersion{Version: version, Files: files}, auth)
+	return err
+}
+
+// Creates a version associated with the project, uploading its files, and returns the created version
+func (project *Project) PublishVersion(version NewVersion, auth string) (*Version, error) {
+	return project.PublishVersionContext(context.Background(), version, auth)
+}
+
+// Creates a version associated with the project, uploading its files, and returns the created version,
+// cancelling the request if the context is done
+func (project *Project) PublishVersionContext(ctx context.Context, version NewVersion, auth string) (*Version, error) {
+	parts, fileTypes, err := uploadParts(version.Files)
+	if err != nil {
+		return nil, err
 	}
 
-	if version.ProjectId == "" {
-		version.ProjectId = project.Id
+	primaryFile := version.PrimaryFile
+	if primaryFile == "" {
+		primaryFile = version.Files[0].Name
+	} else if _, ok := parts[primaryFile]; !ok {
+		return nil, makeValidationError("Primary file %q of version %q is not one of its files", primaryFile, version.Version.Name)
 	}
 
-	files := map[string]io.Reader{}
+	if version.Version.Status == "" {
+		version.Version.Status = "listed"
+	}
 
-	for _, file := range version.FileParts {
-		fileReader, err := os.Open(file)
-		if err != nil {
-			return err
-		}
+	if version.Version.ProjectId == "" {
+		version.Version.ProjectId = project.Id
+	}
+
+	payload, err := toMap(version.Version)
+	if err != nil {
+		return nil, err
+	}
 
-		files[file] = fileReader
+	fileParts := make([]string, 0, len(version.Files))
+	for _, file := range version.Files {
+		fileParts = append(fileParts, file.Name)
 	}
+	payload["file_parts"] = fileParts
+	payload["primary_file"] = primaryFile
+	payload["file_types"] = fileTypes
+	delete(payload, "files")
 
-	body, status, err := post("https://api.modrinth.com/v2/version", version, authHeader(auth), files)
+	c := project.client.orDefault()
+	response, err := c.post(ctx, c.endpoint("version"), payload, c.authHeader(auth), parts)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if status == 200 {
-		return nil
+	if response.status == 400 {
+		return nil, response.error("Invalid request when attempting to create version %q", version.Version.Name)
 	}
 
-	if status == 400 {
-		return makeError("Invalid request when attempting to create version %q: %s", version.Name, string(body))
+	if response.status == 401 {
+		return nil, response.error("No authorisation to create version %q", version.Version.Name)
 	}
 
-	if status == 401 {
-		return makeError("No authorisation to create version %q", version.Name)
+	if response.status != 200 {
+		return nil, response.error("Unexpected status code when creating version %q", version.Version.Name)
 	}
 
-	return makeError("Unexpected status code %d", status)
+	created := Version{}
+	err = json.Unmarshal(response.body, &created)
+	if err != nil {
+		return nil, err
+	}
+	created.attach(c)
+
+	return &created, nil
 }
 
 // Changes the icon of the project
 func (project *Project) ChangeIcon(icon []byte, auth string) error {
-	url := fmt.Sprintf("https://api.modrinth.com/v2/project/%s/icon?ext=%s", project.Id, "png")
-	body, status, err := patch(url, icon, authHeader(auth))
+	return project.ChangeIconContext(context.Background(), icon, auth)
+}
+
+// Changes the icon of the project, cancelling the request if the context is done
+func (project *Project) ChangeIconContext(ctx context.Context, icon []byte, auth string) error {
+	// Fall back to PNG for unrecognised images, and let Modrinth decide whether they're valid
+	ext, ok := imageExtension(icon)
+	if !ok {
+		ext = "png"
+	}
+
+	c := project.client.orDefault()
+	url := c.endpoint("project", project.Id, "icon") + "?ext=" + ext
+	response, err := c.sendRaw(ctx, http.MethodPatch, url, icon, imageContentType(ext), c.authHeader(auth))
 	if err != nil {
 		return err
 	}
 
-	if status == 204 {
+	if response.status == 204 {
 		return nil
 	}
 
-	if status == 400 {
-		return makeError("Invalid request when attempting to modify project icon: %s", string(body))
+	if response.status == 400 {
+		return response.error("Invalid request when attempting to modify project icon")
 	}
 
-	return makeError("Unexpected response, status code %d, error %s", status, string(body))
+	return response.error("Unexpected status code when modifying project icon")
 }
 
-// Modifies the project on Modrinth, updating all non-zero fields
+// Modifies the project on Modrinth, updating all non-zero fields.
+// Use Patch to clear fields
 func (project *Project) Modify(modified Project, auth string) error {
+	return project.ModifyContext(context.Background(), modified, auth)
+}
+
+// Modifies the project on Modrinth, updating all non-zero fields,
+// cancelling the request if the context is done
+func (project *Project) ModifyContext(ctx context.Context, modified Project, auth string) error {
 	overriddenValues, err := removeZeroValues(modified)
 	if err != nil {
 		return err
 	}
 
-	url := "https://api.modrinth.com/v2/project/" + project.Id
-	body, status, err := patch(url, overriddenValues, authHeader(auth))
+	c := project.client.orDefault()
+	url := c.endpoint("project", project.Id)
+	response, err := c.patch(ctx, url, overriddenValues, c.authHeader(auth))
 	if err != nil {
 		return err
 	}
 
-	if status == 204 {
+	if response.status == 204 {
 		if modified.Icon == nil {
 			return nil
 		}
 
-		return project.ChangeIcon(modified.Icon, auth)
+		return project.ChangeIconContext(ctx, modified.Icon, auth)
+	}
+
+	if response.status == 404 {
+		return response.error("Project %q wasn't found or no authorization to see this project", project.Slug)
+	}
+
+	if response.status == 401 {
+		return response.error("No authorisation to modify project %q", project.Slug)
+	}
+
+	if response.status == 400 {
+		return response.error("Invalid request when attempting to modify project %q", project.Slug)
+	}
+
+	return response.error("Unexpected status code when modifying project %q", project.Slug)
+}
+
+// Modifies the project on Modrinth, sending exactly the fields which the patch sets or clears,
+// then fetches the project again so it reflects the changes
+func (project *Project) Patch(patch *ProjectPatch, auth string) error {
+	return project.PatchContext(context.Background(), patch, auth)
+}
+
+// Modifies the project on Modrinth, sending exactly the fields which the patch sets or clears,
+// then fetches the project again so it reflects the changes, cancelling the requests if the context is done
+func (project *Project) PatchContext(ctx context.Context, patch *ProjectPatch, auth string) error {
+	if patch.Empty() {
+		return nil
+	}
+
+	c := project.client.orDefault()
+	response, err := c.patch(ctx, c.endpoint("project", project.Id), patch.fields, c.authHeader(auth))
+	if err != nil {
+		return err
 	}
 
-	if status == 404 {
-		return makeError("Project %q wasn't found or no authorization to see this project", project.Slug)
+	switch response.status {
+	case 204:
+		return project.refresh(ctx, auth)
+	case 400:
+		return response.error("Invalid request when attempting to modify project %q", project.Slug)
+	case 401:
+		return response.error("No authorisation to modify project %q", project.Slug)
+	case 404:
+		return response.error("Project %q wasn't found or no authorization to see this project", project.Slug)
 	}
 
-	if status == 401 {
-		responseSchema := struct {
-			Error       string
-			Description string
-		}{}
+	return response.error("Unexpected status code when modifying project %q", project.Slug)
+}
+
+// Fetches the project again, replacing its fields with the ones on Modrinth
+func (project *Project) refresh(ctx context.Context, auth string) error {
+	if auth == "" {
+		auth = project.auth
+	}
 
-		json.Unmarshal(body, &responseSchema)
+	c := project.client.orDefault()
+	updated, err := c.GetProjectContext(ctx, project.Id, auth)
+	if err != nil {
+		return err
+	}
+
+	// The icon is only ever set locally, so keep it
+	updated.Icon = project.Icon
+	*project = *updated
+	return nil
+}
+
+// Deletes the project from Modrinth. Refuses to delete projects which have been downloaded,
+// returning ErrSafetyCheck, unless the Force option is given
+func (project *Project) Delete(auth string, opts ...Option) error {
+	return project.DeleteContext(context.Background(), auth, opts...)
+}
+
+// Deletes the project from Modrinth, cancelling the requests if the context is done.
+// Refuses to delete projects which have been downloaded, returning ErrSafetyCheck,
+// unless the Force option is given
+func (project *Project) DeleteContext(ctx context.Context, auth string, opts ...Option) error {
+	options := collectOptions(opts)
+	c := project.client.orDefault()
+
+	if !options.force {
+		// Fetch the project again, in case it has been downloaded since it was last fetched
+		current, err := c.GetProjectContext(ctx, project.Id, auth)
+		if err != nil {
+			return err
+		}
+
+		if current.Downloads > 0 {
+			return kindError{
+				gorinthError: makeError("Refusing to delete project %q, which has %d downloads", project.Slug, current.Downloads),
+				kind:         ErrSafetyCheck,
+			}
+		}
+	}
+
+	response, err := c.delete(ctx, c.endpoint("project", project.Id), c.authHeader(auth))
+	if err != nil {
+		return err
+	}
+
+	if response.status == 204 {
+		return nil
+	}
 
-		return makeError("%s: %s", responseSchema.Error, responseSchema.Description)
+	if response.status == 404 {
+		return response.error("Project %q wasn't found or no authorization to see this project", project.Slug)
 	}
 
-	if status == 400 {
-		return makeError("Invalid request when attempting to modify project icon: %s", string(body))
+	if response.status == 401 {
+		return response.error("No authorisation to delete project %q", project.Slug)
 	}
 
-	return makeError("Unexpected response, status code %d, error %s", status, string(body))
+	return response.error("Unexpected status code when deleting project %q", project.Slug)
 }
 
 func validSlug(slug string) bool {
@@ -247,7 +698,7 @@ func (project *Project) Validate() error {
 
 	if project.Title == "" {
 		title := toTitle(project.Slug)
-		logWarning("Invalid project title %q, automatically generated title %q", project.Title, title)
+		project.client.orDefault().logger().Warn("Invalid project title, automatically generated a title", "slug", project.Slug, "title", title)
 		project.Title = title
 	}
 
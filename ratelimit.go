@@ -0,0 +1,138 @@
+package gorinth
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// The number of times a request is sent before giving up on a 429 response
+const maxRateLimitAttempts = 5
+
+// The length of Modrinth's rate limit window, used until a response reports the real reset time
+const rateLimitWindow = time.Minute
+
+// How often waiting requests check the rate limit again, since a response
+// to a request in flight may report that the window resets sooner
+const rateLimitPollInterval = time.Second
+
+// The precision of the X-Ratelimit-Reset header, which is in whole seconds
+const rateLimitResetPrecision = time.Second
+
+// The rate limit state of a client, as reported by the Modrinth API
+type RateLimit struct {
+	// The maximum number of requests which can be sent in one window
+	Limit int
+	// The number of requests which can still be sent before the window resets
+	Remaining int
+	// The time at which the current window resets
+	Reset time.Time
+}
+
+// Tracks the rate limit of a client, shared between all goroutines using it
+type rateLimiter struct {
+	mutex sync.Mutex
+	// Whether a response has reported the rate limit yet
+	known bool
+	state RateLimit
+}
+
+// Parses the X-Ratelimit headers of a response, reporting whether they were present
+func parseRateLimit(header http.Header) (RateLimit, bool) {
+	limit, err := strconv.Atoi(header.Get("X-Ratelimit-Limit"))
+	if err != nil {
+		return RateLimit{}, false
+	}
+	remaining, err := strconv.Atoi(header.Get("X-Ratelimit-Remaining"))
+	if err != nil {
+		return RateLimit{}, false
+	}
+	// The reset header is the number of seconds until the window resets
+	reset, err := strconv.Atoi(header.Get("X-Ratelimit-Reset"))
+	if err != nil {
+		return RateLimit{}, false
+	}
+
+	return RateLimit{
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     time.Now().Add(time.Duration(reset) * time.Second),
+	}, true
+}
+
+// Blocks until a request can be sent without exceeding the rate limit,
+// reserving one request from the remaining budget
+func (l *rateLimiter) wait(ctx context.Context, logger Logger) error {
+	logged := false
+	for {
+		l.mutex.Lock()
+		if !l.known {
+			l.mutex.Unlock()
+			return nil
+		}
+
+		now := time.Now()
+		if !now.Before(l.state.Reset) {
+			// The window has reset, so assume the full budget is available
+			// until a response says otherwise
+			l.state.Remaining = l.state.Limit
+			l.state.Reset = now.Add(rateLimitWindow)
+		}
+
+		if l.state.Remaining > 0 {
+			l.state.Remaining--
+			l.mutex.Unlock()
+			return nil
+		}
+
+		delay := l.state.Reset.Sub(now)
+		l.mutex.Unlock()
+
+		if !logged {
+			logger.Info("Rate limit exhausted, waiting for it to reset", "delay", delay)
+			logged = true
+		}
+
+		if delay > rateLimitPollInterval {
+			delay = rateLimitPollInterval
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// Updates the rate limit from the headers of a response
+func (l *rateLimiter) update(header http.Header) {
+	state, ok := parseRateLimit(header)
+	if !ok {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	// Responses to concurrent requests can arrive out of order, and the server hasn't counted
+	// requests which are still in flight, so only ever lower the remaining budget
+	// while the response is from the window the client is tracking.
+	// A later reset means the server has already started a new window,
+	// which the client's rounded reset time may not have reached yet
+	sameWindow := !state.Reset.After(l.state.Reset.Add(rateLimitResetPrecision))
+	if l.known && sameWindow && time.Now().Before(l.state.Reset) && l.state.Remaining < state.Remaining {
+		state.Remaining = l.state.Remaining
+	}
+
+	l.state = state
+	l.known = true
+}
+
+// Returns the most recently reported rate limit of the client.
+// Reports false if no response has included rate limit information yet
+func (c *Client) RateLimit() (RateLimit, bool) {
+	c.limiter.mutex.Lock()
+	defer c.limiter.mutex.Unlock()
+
+	return c.limiter.state, c.limiter.known
+}
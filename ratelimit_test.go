@@ -0,0 +1,238 @@
+package gorinth
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Creates a client which sends its requests to a test server using the handler
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := NewClient()
+	client.Environment = CustomEnvironment(server.URL)
+	client.HTTPClient = server.Client()
+	client.UserAgent = UserAgent{Name: "gorinth-tests"}
+	return client
+}
+
+func setRateLimit(w http.ResponseWriter, limit int, remaining int, reset int) {
+	w.Header().Set("X-Ratelimit-Limit", strconv.Itoa(limit))
+	w.Header().Set("X-Ratelimit-Remaining", strconv.Itoa(remaining))
+	w.Header().Set("X-Ratelimit-Reset", strconv.Itoa(reset))
+}
+
+func TestRateLimitedRequestIsRetriedAfterReset(t *testing.T) {
+	var mutex sync.Mutex
+	requests := []time.Time{}
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		requests = append(requests, time.Now())
+		count := len(requests)
+		mutex.Unlock()
+
+		if count == 1 {
+			setRateLimit(w, 10, 0, 1)
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		setRateLimit(w, 10, 9, 60)
+		w.Write([]byte(`{"id": "AABBCCDD", "slug": "test"}`))
+	})
+
+	project, err := client.GetProject("test", "")
+	if err != nil {
+		t.Fatalf("GetProject failed: %s", err)
+	}
+	if project.Id != "AABBCCDD" {
+		t.Errorf("got project %q, want AABBCCDD", project.Id)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("server received %d requests, want 2", len(requests))
+	}
+	if waited := requests[1].Sub(requests[0]); waited < 900*time.Millisecond {
+		t.Errorf("retried after %s, want the client to wait for the 1s reset", waited)
+	}
+}
+
+func TestRateLimitedRequestGivesUpWithoutResetHeader(t *testing.T) {
+	requests := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	_, err := client.GetProject("test", "")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("got error %v, want ErrRateLimited", err)
+	}
+	if requests != 1 {
+		t.Errorf("server received %d requests, want 1", requests)
+	}
+}
+
+func TestExhaustedRateLimitBlocksUntilReset(t *testing.T) {
+	var mutex sync.Mutex
+	requests := []time.Time{}
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		requests = append(requests, time.Now())
+		count := len(requests)
+		mutex.Unlock()
+
+		if count == 1 {
+			setRateLimit(w, 10, 0, 1)
+		} else {
+			setRateLimit(w, 10, 9, 60)
+		}
+		w.Write([]byte(`{"id": "AABBCCDD"}`))
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetProject("test", ""); err != nil {
+			t.Fatalf("GetProject failed: %s", err)
+		}
+	}
+
+	rateLimit, ok := client.RateLimit()
+	if !ok || rateLimit.Limit != 10 || rateLimit.Remaining != 9 {
+		t.Errorf("RateLimit() = %+v, %t, want the limit from the last response", rateLimit, ok)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("server received %d requests, want 2", len(requests))
+	}
+	if waited := requests[1].Sub(requests[0]); waited < 900*time.Millisecond {
+		t.Errorf("second request sent after %s, want the client to wait for the 1s reset", waited)
+	}
+}
+
+// A server which allows a fixed number of requests per window, like Modrinth,
+// counting any requests it has to reject
+type windowedServer struct {
+	mutex       sync.Mutex
+	limit       int
+	window      time.Duration
+	windowStart time.Time
+	count       int
+	served      int
+	rejected    int
+}
+
+func (s *windowedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.windowStart) >= s.window {
+		s.windowStart = now
+		s.count = 0
+	}
+	s.count++
+
+	remaining := s.limit - s.count
+	if remaining < 0 {
+		remaining = 0
+	}
+	reset := int(math.Ceil(s.windowStart.Add(s.window).Sub(now).Seconds()))
+	setRateLimit(w, s.limit, remaining, reset)
+
+	if s.count > s.limit {
+		s.rejected++
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+
+	s.served++
+	w.Write([]byte(`{"id": "AABBCCDD"}`))
+}
+
+func TestConcurrentRequestsStayWithinRateLimit(t *testing.T) {
+	server := &windowedServer{limit: 20, window: time.Second}
+	client := newTestClient(t, server.ServeHTTP)
+
+	// The limit is only known once a response has reported it
+	if _, err := client.GetProject("test", ""); err != nil {
+		t.Fatalf("GetProject failed: %s", err)
+	}
+
+	const goroutines = 50
+	var wait sync.WaitGroup
+	errs := make(chan error, goroutines)
+	for i := 0; i < goroutines; i++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			if _, err := client.GetProject("test", ""); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wait.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("GetProject failed: %s", err)
+	}
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	if server.rejected != 0 {
+		t.Errorf("server rejected %d requests for exceeding the rate limit, want 0", server.rejected)
+	}
+	if server.served != goroutines+1 {
+		t.Errorf("server served %d requests, want %d", server.served, goroutines+1)
+	}
+}
+
+// Returns response headers reporting the rate limit
+func rateLimitHeader(limit int, remaining int, reset int) http.Header {
+	recorder := httptest.NewRecorder()
+	setRateLimit(recorder, limit, remaining, reset)
+	return recorder.Header()
+}
+
+func TestRateLimitWindowRollsOverWhileRequestIsInFlight(t *testing.T) {
+	limiter := &rateLimiter{}
+	limiter.update(rateLimitHeader(300, 0, 1))
+
+	// The server's window rolled over before the rounded reset time,
+	// and a request in flight reports the new window
+	time.Sleep(300 * time.Millisecond)
+	limiter.update(rateLimitHeader(300, 299, 60))
+
+	if limiter.state.Remaining != 299 {
+		t.Errorf("remaining is %d after the window rolled over, want 299", limiter.state.Remaining)
+	}
+
+	start := time.Now()
+	if err := limiter.wait(context.Background(), nopLogger{}); err != nil {
+		t.Fatalf("wait failed: %s", err)
+	}
+	if waited := time.Since(start); waited > 100*time.Millisecond {
+		t.Errorf("waited %s with budget left in the new window", waited)
+	}
+}
+
+func TestOutOfOrderResponsesOnlyLowerRemaining(t *testing.T) {
+	limiter := &rateLimiter{}
+	limiter.update(rateLimitHeader(300, 100, 60))
+	limiter.update(rateLimitHeader(300, 120, 60))
+
+	if limiter.state.Remaining != 100 {
+		t.Errorf("remaining is %d after an older response, want 100", limiter.state.Remaining)
+	}
+}
@@ -0,0 +1,128 @@
+package gorinth
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// Determines how requests are retried after transient failures,
+// such as connection resets or 502 responses
+type RetryPolicy struct {
+	// The maximum number of times a request is sent, including the first attempt.
+	// Values below 2 disable retries
+	MaxAttempts int
+	// The delay before the first retry, doubled for each subsequent retry
+	BaseDelay time.Duration
+	// The maximum delay between two attempts. Zero means the delay is never capped
+	MaxDelay time.Duration
+	// The status codes of responses which are retried
+	RetryableStatuses []int
+	// Whether requests which aren't idempotent, such as the multipart uploads
+	// sent when creating versions, are retried too. Only GET, HEAD, PUT and DELETE
+	// requests are retried otherwise
+	RetryNonIdempotent bool
+}
+
+// The retry policy used by clients which don't set one
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+	RetryableStatuses: []int{
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	},
+}
+
+func (c *Client) retryPolicy() *RetryPolicy {
+	if c.RetryPolicy == nil {
+		return &DefaultRetryPolicy
+	}
+	return c.RetryPolicy
+}
+
+// Reports whether a request which has been sent the given number of times can be sent again
+func (policy *RetryPolicy) canRetry(request *http.Request, attempt int) bool {
+	if attempt >= policy.MaxAttempts {
+		return false
+	}
+
+	switch request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return policy.RetryNonIdempotent
+}
+
+// Reports whether a request which failed with the given error might succeed if sent again.
+// Only network failures which are likely to be temporary are retried,
+// not errors such as invalid certificates or unsupported URL schemes
+func retryableError(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var netError net.Error
+	return errors.As(err, &netError) && netError.Timeout()
+}
+
+func (policy *RetryPolicy) retryableStatus(status int) bool {
+	for _, retryable := range policy.RetryableStatuses {
+		if status == retryable {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the delay after the given attempt, using exponential backoff with jitter
+func (policy *RetryPolicy) delay(attempt int) time.Duration {
+	delay := policy.BaseDelay
+	for i := 1; i < attempt; i++ {
+		if policy.MaxDelay > 0 && delay >= policy.MaxDelay {
+			break
+		}
+		// Stop doubling before the delay overflows
+		if delay > math.MaxInt64/2 {
+			break
+		}
+		delay *= 2
+	}
+	if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	// Wait somewhere between half and all of the delay,
+	// so that clients which failed together don't retry together
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// Waits for the given duration, returning early if the context is done
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,255 @@
+package gorinth
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// A retry policy which retries quickly, so tests don't have to wait
+func fastRetryPolicy(maxAttempts int) *RetryPolicy {
+	policy := DefaultRetryPolicy
+	policy.MaxAttempts = maxAttempts
+	policy.BaseDelay = time.Millisecond
+	policy.MaxDelay = 5 * time.Millisecond
+	return &policy
+}
+
+// Counts the requests received by a test server, and keeps their bodies
+type requestLog struct {
+	mutex  sync.Mutex
+	bodies [][]byte
+}
+
+func (l *requestLog) add(r *http.Request) int {
+	body, _ := io.ReadAll(r.Body)
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.bodies = append(l.bodies, body)
+	return len(l.bodies)
+}
+
+func (l *requestLog) count() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return len(l.bodies)
+}
+
+func TestGetIsRetriedOnBadGateway(t *testing.T) {
+	requests := &requestLog{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if requests.add(r) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"id": "AABBCCDD"}`))
+	})
+	client.RetryPolicy = fastRetryPolicy(3)
+
+	if _, err := client.GetProject("test", ""); err != nil {
+		t.Fatalf("GetProject failed: %s", err)
+	}
+	if requests.count() != 3 {
+		t.Errorf("server received %d requests, want 3", requests.count())
+	}
+}
+
+func TestGetIsRetriedOnConnectionReset(t *testing.T) {
+	requests := &requestLog{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if requests.add(r) == 1 {
+			// Drop the connection without responding
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Errorf("failed to hijack connection: %s", err)
+				return
+			}
+			conn.Close()
+			return
+		}
+		w.Write([]byte(`{"id": "AABBCCDD"}`))
+	})
+	client.RetryPolicy = fastRetryPolicy(3)
+
+	if _, err := client.GetProject("test", ""); err != nil {
+		t.Fatalf("GetProject failed: %s", err)
+	}
+	if requests.count() != 2 {
+		t.Errorf("server received %d requests, want 2", requests.count())
+	}
+}
+
+// Publishes a version with one file to a server which always responds with a 502
+func publishToFailingServer(t *testing.T, policy *RetryPolicy) (*requestLog, error) {
+	requests := &requestLog{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.add(r)
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	client.RetryPolicy = policy
+
+	project := &Project{Id: "AABBCCDD", Slug: "test", client: client}
+	_, err := project.PublishVersion(NewVersion{
+		Version: Version{Name: "Test", VersionNumber: "1.0.0", VersionType: ReleaseRelease},
+		Files:   []FileUpload{{Name: "test.jar", Data: bytes.NewReader([]byte("jar contents"))}},
+	}, "token")
+	return requests, err
+}
+
+func TestMultipartUploadIsNotRetriedByDefault(t *testing.T) {
+	requests, err := publishToFailingServer(t, fastRetryPolicy(3))
+
+	var apiError *APIError
+	if !errors.As(err, &apiError) || apiError.StatusCode != http.StatusBadGateway {
+		t.Errorf("got error %v, want a 502 APIError", err)
+	}
+	if requests.count() != 1 {
+		t.Errorf("server received %d requests, want 1", requests.count())
+	}
+}
+
+func TestMultipartUploadIsRetriedWhenAllowed(t *testing.T) {
+	policy := fastRetryPolicy(3)
+	policy.RetryNonIdempotent = true
+	requests, _ := publishToFailingServer(t, policy)
+
+	if requests.count() != 3 {
+		t.Fatalf("server received %d requests, want 3", requests.count())
+	}
+	for i, body := range requests.bodies {
+		if !bytes.Contains(body, []byte("jar contents")) {
+			t.Errorf("attempt %d was sent without the uploaded file", i+1)
+		}
+		if !bytes.Equal(body, requests.bodies[0]) {
+			t.Errorf("attempt %d sent a different body to the first attempt", i+1)
+		}
+	}
+}
+
+func TestRetriesGiveUpAtMaxAttempts(t *testing.T) {
+	requests := &requestLog{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.add(r)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	client.RetryPolicy = fastRetryPolicy(4)
+
+	_, err := client.GetProject("test", "")
+
+	var apiError *APIError
+	if !errors.As(err, &apiError) || apiError.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("got error %v, want a 503 APIError", err)
+	}
+	if requests.count() != 4 {
+		t.Errorf("server received %d requests, want 4", requests.count())
+	}
+}
+
+func TestRetriesStopWhenContextIsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	requests := &requestLog{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.add(r)
+		// Cancel while the client is waiting to retry
+		cancel()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	policy := fastRetryPolicy(5)
+	policy.BaseDelay = time.Minute
+	policy.MaxDelay = time.Minute
+	client.RetryPolicy = policy
+
+	start := time.Now()
+	_, err := client.GetProjectContext(ctx, "test", "")
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("returned after %s, want it to stop waiting once cancelled", elapsed)
+	}
+	if requests.count() != 1 {
+		t.Errorf("server received %d requests, want 1", requests.count())
+	}
+}
+
+func TestDelayDoublesWithoutMaxDelay(t *testing.T) {
+	policy := &RetryPolicy{MaxAttempts: 6, BaseDelay: time.Second}
+
+	for attempt := 1; attempt <= 5; attempt++ {
+		full := time.Second << (attempt - 1)
+		if delay := policy.delay(attempt); delay < full/2 || delay > full {
+			t.Errorf("delay after attempt %d is %s, want between %s and %s", attempt, delay, full/2, full)
+		}
+	}
+
+	if delay := policy.delay(100); delay <= 0 {
+		t.Errorf("delay after attempt 100 is %s, want it to stop growing rather than overflow", delay)
+	}
+}
+
+// Counts the requests sent through it, failing each with an error
+type failingTransport struct {
+	err      error
+	requests int
+}
+
+func (t *failingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	t.requests++
+	return nil, t.err
+}
+
+func TestTransportErrorsAreRetriedOnlyIfTransient(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		attempts int
+	}{
+		{"connection reset", syscall.ECONNRESET, 3},
+		{"unexpected EOF", io.ErrUnexpectedEOF, 3},
+		{"timeout", &net.DNSError{Err: "timeout", IsTimeout: true}, 3},
+		{"permanent error", errors.New("no recorded response"), 1},
+		{"unknown authority", x509.UnknownAuthorityError{}, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			transport := &failingTransport{err: test.err}
+			client := NewClient()
+			client.HTTPClient = &http.Client{Transport: transport}
+			client.RetryPolicy = fastRetryPolicy(3)
+
+			if _, err := client.GetProject("test", ""); err == nil {
+				t.Fatal("GetProject succeeded, want an error")
+			}
+			if transport.requests != test.attempts {
+				t.Errorf("sent %d requests, want %d", transport.requests, test.attempts)
+			}
+		})
+	}
+}
+
+func TestUnsupportedSchemeIsNotRetried(t *testing.T) {
+	client := NewClient()
+	client.Environment = CustomEnvironment("ftp://example.com")
+	client.RetryPolicy = &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Minute}
+
+	start := time.Now()
+	if _, err := client.GetProject("test", ""); err == nil {
+		t.Fatal("GetProject succeeded, want an error")
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("returned after %s, want it to fail without retrying", elapsed)
+	}
+}
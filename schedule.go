@@ -0,0 +1,59 @@
+package gorinth
+
+import (
+	"context"
+	"time"
+)
+
+// Reports whether the status can be requested when scheduling a project
+func (status RequestedProjectStatus) valid() bool {
+	switch status {
+	case RequestedProjectApproved, RequestedProjectArchived, RequestedProjectUnlisted,
+		RequestedProjectPrivate, RequestedProjectDraft:
+		return true
+	}
+	return false
+}
+
+// Schedules the project to be given the requested status at the given time, which must be in the future
+func (project *Project) Schedule(at time.Time, status RequestedProjectStatus, auth string) error {
+	return project.ScheduleContext(context.Background(), at, status, auth)
+}
+
+// Schedules the project to be given the requested status at the given time, which must be in the future,
+// cancelling the request if the context is done
+func (project *Project) ScheduleContext(ctx context.Context, at time.Time, status RequestedProjectStatus, auth string) error {
+	if !at.After(time.Now()) {
+		return makeValidationError("Cannot schedule project %q for %s, which is in the past", project.Slug, at.Format(time.RFC3339))
+	}
+
+	if !status.valid() {
+		return makeValidationError("Cannot schedule project %q with status %q, must be one of approved, archived, unlisted, private or draft", project.Slug, status)
+	}
+
+	payload := map[string]any{
+		"time":             at.UTC().Format(time.RFC3339),
+		"requested_status": status,
+	}
+
+	c := project.client.orDefault()
+	response, err := c.postJSON(ctx, c.endpoint("project", project.Id, "schedule"), payload, c.authHeader(auth))
+	if err != nil {
+		return err
+	}
+
+	switch response.status {
+	case 204:
+		project.Status = ProjectScheduled
+		project.RequestedStatus = &status
+		return nil
+	case 400:
+		return response.error("Invalid request when attempting to schedule project %q", project.Slug)
+	case 401:
+		return response.error("No authorisation to schedule project %q", project.Slug)
+	case 404:
+		return response.error("Project %q wasn't found or no authorization to see this project", project.Slug)
+	}
+
+	return response.error("Unexpected status code when scheduling project %q", project.Slug)
+}
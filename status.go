@@ -0,0 +1,100 @@
+package gorinth
+
+import "context"
+
+// Reports whether a project's owner may change its status from one status to another.
+// Any project which hasn't been approved can be submitted for review, and only projects
+// which have been approved can be archived, unlisted, made private or returned to draft
+func canChangeStatus(from ProjectStatus, to ProjectStatus) bool {
+	approved := from == ProjectApproved || from == ProjectArchived ||
+		from == ProjectUnlisted || from == ProjectPrivate
+
+	switch to {
+	case ProjectProcessing:
+		return !approved
+	case ProjectApproved, ProjectArchived, ProjectUnlisted, ProjectPrivate, ProjectDraft:
+		return approved
+	}
+	return false
+}
+
+// Submits the draft project to Modrinth's moderators for review
+func (project *Project) SubmitForReview(auth string) error {
+	return project.SubmitForReviewContext(context.Background(), auth)
+}
+
+// Submits the draft project to Modrinth's moderators for review,
+// cancelling the requests if the context is done
+func (project *Project) SubmitForReviewContext(ctx context.Context, auth string) error {
+	return project.changeStatus(ctx, ProjectProcessing, auth)
+}
+
+// Archives the project, marking it as no longer maintained
+func (project *Project) Archive(auth string) error {
+	return project.ArchiveContext(context.Background(), auth)
+}
+
+// Archives the project, marking it as no longer maintained,
+// cancelling the requests if the context is done
+func (project *Project) ArchiveContext(ctx context.Context, auth string) error {
+	return project.changeStatus(ctx, ProjectArchived, auth)
+}
+
+// Unlists the project, hiding it from search while keeping it accessible by link
+func (project *Project) Unlist(auth string) error {
+	return project.UnlistContext(context.Background(), auth)
+}
+
+// Unlists the project, hiding it from search while keeping it accessible by link,
+// cancelling the requests if the context is done
+func (project *Project) UnlistContext(ctx context.Context, auth string) error {
+	return project.changeStatus(ctx, ProjectUnlisted, auth)
+}
+
+// Makes the project private, so only its members can see it
+func (project *Project) MakePrivate(auth string) error {
+	return project.MakePrivateContext(context.Background(), auth)
+}
+
+// Makes the project private, so only its members can see it,
+// cancelling the requests if the context is done
+func (project *Project) MakePrivateContext(ctx context.Context, auth string) error {
+	return project.changeStatus(ctx, ProjectPrivate, auth)
+}
+
+// Returns the project to a draft, so only its members can see it until it is submitted again
+func (project *Project) ReturnToDraft(auth string) error {
+	return project.ReturnToDraftContext(context.Background(), auth)
+}
+
+// Returns the project to a draft, so only its members can see it until it is submitted again,
+// cancelling the requests if the context is done
+func (project *Project) ReturnToDraftContext(ctx context.Context, auth string) error {
+	return project.changeStatus(ctx, ProjectDraft, auth)
+}
+
+// Changes the project's status, then fetches the project again so it reflects the status Modrinth gave it
+func (project *Project) changeStatus(ctx context.Context, status ProjectStatus, auth string) error {
+	if !canChangeStatus(project.Status, status) {
+		return makeValidationError("Cannot change status of project %q from %s to %s", project.Slug, project.Status, status)
+	}
+
+	c := project.client.orDefault()
+	response, err := c.patch(ctx, c.endpoint("project", project.Id), map[string]any{"status": status}, c.authHeader(auth))
+	if err != nil {
+		return err
+	}
+
+	switch response.status {
+	case 204:
+		return project.refresh(ctx, auth)
+	case 400:
+		return response.error("Invalid request when attempting to change status of project %q to %s", project.Slug, status)
+	case 401:
+		return response.error("No authorisation to change status of project %q to %s", project.Slug, status)
+	case 404:
+		return response.error("Project %q wasn't found or no authorization to see this project", project.Slug)
+	}
+
+	return response.error("Unexpected status code when changing status of project %q to %s", project.Slug, status)
+}
@@ -1,5 +1,7 @@
 package gorinth
 
+import "io"
+
 // A donation link for a project
 type ProjectDonationURL struct {
 	// The ID of the donation platform
@@ -47,13 +49,34 @@ type Dependency struct {
 	FileName *string `json:"file_name"`
 	// The type of the dependency
 	DependencyType DependencyType `json:"dependency_type"`
+	client         *Client
+}
+
+// A version to create with Project.PublishVersion
+type NewVersion struct {
+	// The details of the version. Its Files and FileParts are ignored
+	Version Version
+	// The files to upload with the version
+	Files []FileUpload
+	// The name of the primary file. Defaults to the first file
+	PrimaryFile string
+}
+
+// A file to upload to a version
+type FileUpload struct {
+	// The name of the file, including its extension
+	Name string
+	// The contents of the file
+	Data io.Reader
+	// The type of the file, for resource packs which accompany a datapack
+	FileType *FileType
 }
 
 type VersionFile struct {
 	// A map of hashing algorithms to hashes of the file
 	Hashes struct {
-		sha512 string
-		sha1   string
+		Sha512 string `json:"sha512"`
+		Sha1   string `json:"sha1"`
 	} `json:"hashes"`
 	// The url of the file
 	Url string `json:"url"`
@@ -78,3 +101,52 @@ type PayoutData struct {
 	// The user's payout address
 	PayoutAddress string `json:"payout_address"`
 }
+
+// An image to add to the gallery of a project. Pointer fields are optional
+type NewGalleryImage struct {
+	// The image data. The image type is detected from the data
+	Image []byte
+	// Whether or not the image is featured in the gallery
+	Featured bool
+	// The title of the image
+	Title *string
+	// The description of the image
+	Description *string
+	// The order of the gallery image.
+	// Gallery images are sorted by this field and then alphabetically by title
+	Ordering *int
+}
+
+// Changes to make to an image in the gallery of a project. Only non-nil fields are changed
+type GalleryImageChanges struct {
+	// Whether or not the image is featured in the gallery
+	Featured *bool
+	// The title of the image
+	Title *string
+	// The description of the image
+	Description *string
+	// The order of the gallery image
+	Ordering *int
+}
+
+// The projects and versions that the versions of a project depend on
+type ProjectDependencies struct {
+	// Every project that any version of the project depends on
+	Projects []Project `json:"projects"`
+	// Every specific version that any version of the project depends on
+	Versions []Version `json:"versions"`
+	// Each dependency of the linked versions, along with what it refers to. Empty until Link is called
+	Links []DependencyLink `json:"-"`
+}
+
+// A dependency of a version, linked to the project and version it refers to. Pointer fields are optional
+type DependencyLink struct {
+	// The version which declares the dependency
+	Dependent *Version
+	// The dependency entry in the dependent version's Dependencies
+	Dependency Dependency
+	// The project that is depended on
+	Project *Project
+	// The version that is depended on. Only present if the dependency is on a specific version
+	Version *Version
+}
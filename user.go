@@ -2,44 +2,85 @@ package gorinth
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
+	"net/http"
 )
 
 // Returns the user associated with an auth token
 func GetUserFromAuth(auth string) (*User, error) {
-	body, status, err := get("https://api.modrinth.com/v2/user", authHeader(auth))
+	return DefaultClient.GetUserFromAuth(auth)
+}
+
+// Returns the user associated with an auth token, cancelling the request if the context is done
+func GetUserFromAuthContext(ctx context.Context, auth string) (*User, error) {
+	return DefaultClient.GetUserFromAuthContext(ctx, auth)
+}
+
+// Returns the user associated with an auth token
+func (c *Client) GetUserFromAuth(auth string) (*User, error) {
+	return c.GetUserFromAuthContext(context.Background(), auth)
+}
+
+// Returns the user associated with an auth token, cancelling the request if the context is done
+func (c *Client) GetUserFromAuthContext(ctx context.Context, auth string) (*User, error) {
+	response, err := c.get(ctx, c.endpoint("user"), c.authHeader(auth))
 	if err != nil {
 		return nil, err
 	}
 
-	if status == 401 {
-		return nil, makeError("Invalid authorisation token given")
+	if response.status == 401 {
+		return nil, response.error("Invalid authorisation token given")
 	}
 
-	if status == 200 {
+	if response.status == 200 {
 		user := User{}
 
-		err := json.Unmarshal(body, &user)
+		err := json.Unmarshal(response.body, &user)
 		if err != nil {
 			return nil, makeError(err.Error())
 		}
 		user.auth = auth
+		user.client = c
 
 		return &user, nil
 	}
 
-	return nil, makeError("Unexpected response status %d", status)
+	return nil, response.error("Unexpected status code when fetching user")
 }
 
 // Creates a project on the user's profile
-func (user *User) CreateProject(project Project) error {
+func (user *User) CreateProject(project Project, opts ...Option) error {
+	return user.CreateProjectContext(context.Background(), project, opts...)
+}
+
+// Creates a project on the user's profile, cancelling the request if the context is done
+func (user *User) CreateProjectContext(ctx context.Context, project Project, opts ...Option) error {
+	options := collectOptions(opts)
+	c := user.client.orDefault()
+
+	project.client = c
 	project.Validate()
 	overriddenValues, err := removeNullValues(project)
 	if err != nil {
 		return err
 	}
 
+	if options.checkSlug {
+		id, exists, err := c.CheckProjectContext(ctx, project.Slug, user.auth)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			return validationError{
+				gorinthError: makeError("Cannot create project %q, slug %q is already used by project %s", project.Title, project.Slug, id),
+				kind:         ErrSlugTaken,
+			}
+		}
+	}
+
 	overriddenValues["license_id"] = project.License.Id
 	overriddenValues["is_draft"] = true
 
@@ -49,27 +90,113 @@ func (user *User) CreateProject(project Project) error {
 		parts["icon"] = bytes.NewBuffer(project.Icon)
 	}
 
-	body, status, err := post(
-		"https://api.modrinth.com/v2/project",
+	response, err := c.post(
+		ctx,
+		c.endpoint("project"),
 		overriddenValues,
-		authHeader(user.auth),
+		c.authHeader(user.auth),
 		parts,
 	)
 	if err != nil {
 		return err
 	}
 
-	if status == 200 {
+	if response.status == 200 {
+		return nil
+	}
+
+	if response.status == 400 {
+		return response.error("invalid request when attempting to create project %q", project.Title)
+	}
+
+	if response.status == 401 {
+		return response.error("no authorisation to create project %q", project.Title)
+	}
+
+	return response.error("unexpected status code when creating project %q", project.Title)
+}
+
+// Follows the project as the user
+func (user *User) Follow(project *Project) error {
+	return user.FollowContext(context.Background(), project)
+}
+
+// Follows the project as the user, cancelling the request if the context is done
+func (user *User) FollowContext(ctx context.Context, project *Project) error {
+	c := user.client.orDefault()
+	response, err := c.sendRaw(ctx, http.MethodPost, c.endpoint("project", project.Id, "follow"), nil, "", c.authHeader(user.auth))
+	if err != nil {
+		return err
+	}
+
+	return followError(response, "follow", project)
+}
+
+// Stops following the project as the user
+func (user *User) Unfollow(project *Project) error {
+	return user.UnfollowContext(context.Background(), project)
+}
+
+// Stops following the project as the user, cancelling the request if the context is done
+func (user *User) UnfollowContext(ctx context.Context, project *Project) error {
+	c := user.client.orDefault()
+	response, err := c.delete(ctx, c.endpoint("project", project.Id, "follow"), c.authHeader(user.auth))
+	if err != nil {
+		return err
+	}
+
+	return followError(response, "unfollow", project)
+}
+
+func followError(response *response, action string, project *Project) error {
+	switch response.status {
+	case 204:
 		return nil
+	case 400:
+		return response.error("Invalid request when attempting to %s project %q", action, project.Slug)
+	case 401:
+		return response.error("No authorisation to %s project %q", action, project.Slug)
+	case 404:
+		return response.error("Project %q wasn't found or no authorization to see this project", project.Slug)
+	}
+	return response.error("Unexpected status code when attempting to %s project %q", action, project.Slug)
+}
+
+// Returns the projects that the user follows
+func (user *User) GetFollowedProjects() ([]Project, error) {
+	return user.GetFollowedProjectsContext(context.Background())
+}
+
+// Returns the projects that the user follows, cancelling the request if the context is done
+func (user *User) GetFollowedProjectsContext(ctx context.Context) ([]Project, error) {
+	c := user.client.orDefault()
+	response, err := c.get(ctx, c.endpoint("user", user.Id, "follows"), c.authHeader(user.auth))
+	if err != nil {
+		return nil, err
+	}
+
+	if response.status == 401 {
+		return nil, response.error("No authorisation to see the projects followed by %q", user.Username)
+	}
+
+	if response.status == 404 {
+		return nil, response.error("User %q wasn't found", user.Username)
 	}
 
-	if status == 400 {
-		return makeError("invalid request when attempting to create project %q: %s", project.Title, string(body))
+	if response.status != 200 {
+		return nil, response.error("Unexpected status code when fetching the projects followed by %q", user.Username)
+	}
+
+	projects := []Project{}
+	err = json.Unmarshal(response.body, &projects)
+	if err != nil {
+		return nil, err
 	}
 
-	if status == 401 {
-		return makeError("no authorisation to create project %q", project.Title)
+	for i := range projects {
+		projects[i].auth = user.auth
+		projects[i].client = c
 	}
 
-	return makeError("unexpected status code %d", status)
+	return projects, nil
 }
@@ -0,0 +1,43 @@
+package gorinth
+
+import (
+	"strings"
+)
+
+// The version of gorinth, included in the User-Agent of every request
+const libraryVersion = "0.1.0-beta-1"
+
+// Identifies the application using gorinth to Modrinth,
+// which asks every API consumer to send a uniquely identifying User-Agent
+type UserAgent struct {
+	// The name of the application, ideally including its author, such as "gearsdatapacks/my-tool"
+	Name string
+	// The version of the application
+	Version string
+	// A way to contact the application's developers, such as an email address or website
+	Contact string
+}
+
+// Returns the User-Agent header, in the form "name/version (contact) gorinth/version"
+func (agent UserAgent) String() string {
+	userAgent := agent.Name
+	// A version means nothing without the name of the application it belongs to
+	if agent.Name != "" && agent.Version != "" {
+		userAgent += "/" + agent.Version
+	}
+	if agent.Contact != "" {
+		userAgent += " (" + agent.Contact + ")"
+	}
+
+	return strings.TrimSpace(userAgent + " gorinth/" + libraryVersion)
+}
+
+func (c *Client) userAgent() string {
+	if c.UserAgent.Name == "" {
+		c.userAgentWarning.Do(func() {
+			c.logger().Warn("No user agent has been set on the client. Modrinth may block requests from unidentified applications")
+		})
+	}
+
+	return c.UserAgent.String()
+}
@@ -0,0 +1,23 @@
+package gorinth
+
+import "testing"
+
+func TestUserAgentString(t *testing.T) {
+	tests := []struct {
+		agent UserAgent
+		want  string
+	}{
+		{UserAgent{}, "gorinth/" + libraryVersion},
+		{UserAgent{Name: "me/tool"}, "me/tool gorinth/" + libraryVersion},
+		{UserAgent{Name: "me/tool", Version: "1.0.0"}, "me/tool/1.0.0 gorinth/" + libraryVersion},
+		{UserAgent{Name: "me/tool", Version: "1.0.0", Contact: "me@example.com"}, "me/tool/1.0.0 (me@example.com) gorinth/" + libraryVersion},
+		{UserAgent{Version: "1.0.0"}, "gorinth/" + libraryVersion},
+		{UserAgent{Version: "1.0.0", Contact: "me@example.com"}, "(me@example.com) gorinth/" + libraryVersion},
+	}
+
+	for _, test := range tests {
+		if got := test.agent.String(); got != test.want {
+			t.Errorf("%+v.String() = %q, want %q", test.agent, got, test.want)
+		}
+	}
+}
@@ -2,83 +2,224 @@ package gorinth
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"mime/multipart"
 	"net/http"
-	"os"
+	"sort"
 )
 
-func authHeader(auth string) map[string]string {
-	return map[string]string{"Authorization": auth}
+// A response received from the Modrinth API
+type response struct {
+	body   []byte
+	status int
+	header http.Header
+	// The method and URL of the request
+	endpoint string
 }
 
-func get(url string, headers map[string]string) (body []byte, status int, err error) {
-	client := &http.Client{}
-	request, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		return nil, 0, err
-	}
+// Creates an *APIError from the response, describing what was being attempted
+func (r *response) error(message string, values ...any) *APIError {
+	return newAPIError(r.endpoint, r.status, r.body, message, values...)
+}
 
+func (c *Client) send(request *http.Request, headers map[string]string) (*response, error) {
+	request.Header.Set("User-Agent", c.userAgent())
+	for key, value := range c.Headers {
+		request.Header.Set(key, value)
+	}
 	for key, value := range headers {
 		request.Header.Set(key, value)
 	}
 
-	response, err := client.Do(request)
+	logger := c.logger()
+	policy := c.retryPolicy()
+	rateLimitAttempts := 0
+	attempt := 1
+
+	for {
+		if err := c.limiter.wait(request.Context(), logger); err != nil {
+			return nil, err
+		}
+
+		logger.Debug("Sending request",
+			"method", request.Method,
+			"url", request.URL.String(),
+			"headers", redactHeaders(request.Header),
+			"attempt", attempt,
+		)
+
+		response, err := c.sendOnce(request)
+		if err != nil {
+			if request.Context().Err() != nil || !retryableError(err) || !policy.canRetry(request, attempt) {
+				return nil, err
+			}
+		} else {
+			logger.Debug("Received response",
+				"endpoint", response.endpoint,
+				"status", response.status,
+				"headers", redactHeaders(response.header),
+			)
+			c.limiter.update(response.header)
+
+			if response.status == http.StatusTooManyRequests {
+				rateLimitAttempts++
+				// Without a reset time there's no way to know how long to wait for
+				if _, ok := parseRateLimit(response.header); !ok || rateLimitAttempts >= maxRateLimitAttempts {
+					return response, nil
+				}
+
+				logger.Info("Rate limited, retrying once the rate limit resets", "endpoint", response.endpoint)
+
+				// The limiter waits for the window to reset before the next attempt
+				request, err = rewind(request)
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			if !policy.retryableStatus(response.status) || !policy.canRetry(request, attempt) {
+				return response, nil
+			}
+		}
+
+		delay := policy.delay(attempt)
+		if err != nil {
+			logger.Info("Request failed, retrying",
+				"method", request.Method,
+				"url", request.URL.String(),
+				"attempt", attempt,
+				"delay", delay,
+				"error", err,
+			)
+		} else {
+			logger.Info("Received transient error response, retrying",
+				"endpoint", response.endpoint,
+				"status", response.status,
+				"attempt", attempt,
+				"delay", delay,
+			)
+		}
+
+		if err := sleep(request.Context(), delay); err != nil {
+			return nil, err
+		}
+
+		request, err = rewind(request)
+		if err != nil {
+			return nil, err
+		}
+		attempt++
+	}
+}
+
+// Returns a copy of the request which can be sent again
+func rewind(request *http.Request) (*http.Request, error) {
+	clone := request.Clone(request.Context())
+	if request.GetBody != nil {
+		body, err := request.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
+
+func (c *Client) sendOnce(request *http.Request) (*response, error) {
+	httpResponse, err := c.httpClient().Do(request)
 	if err != nil {
-		return nil, 0, err
+		// Report cancellation as the context's own error,
+		// so it can be told apart from network failures
+		if ctxErr := request.Context().Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
 	}
 
-	defer response.Body.Close()
-	responseBody, err := io.ReadAll(response.Body)
+	defer httpResponse.Body.Close()
+	responseBody, err := io.ReadAll(httpResponse.Body)
 	if err != nil {
-		return nil, 0, err
+		if ctxErr := request.Context().Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
 	}
 
-	return responseBody, response.StatusCode, nil
+	return &response{
+		body:     responseBody,
+		status:   httpResponse.StatusCode,
+		header:   httpResponse.Header,
+		endpoint: request.Method + " " + request.URL.String(),
+	}, nil
 }
 
-func patch(url string, payload any, headers map[string]string) (body []byte, status int, err error) {
-	var requestSchema []byte
-	if bytes, ok := payload.([]byte); ok {
-		requestSchema = bytes
-	} else {
-		var err error
-		requestSchema, err = json.Marshal(payload)
-		if err != nil {
-			return nil, 0, err
-		}
+func (c *Client) get(ctx context.Context, url string, headers map[string]string) (*response, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	client := &http.Client{}
-	request, err := http.NewRequest(http.MethodPatch, url, bytes.NewBuffer(requestSchema))
+	return c.send(request, headers)
+}
+
+func (c *Client) delete(ctx context.Context, url string, headers map[string]string) (*response, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	request.Header.Set("Content-Type", "application/json")
-	for key, value := range headers {
-		request.Header.Set(key, value)
+	return c.send(request, headers)
+}
+
+// Sends a request whose body is raw data of the given content type, such as an image.
+// The body may be empty, in which case the content type is omitted
+func (c *Client) sendRaw(ctx context.Context, method string, url string, body []byte, contentType string, headers map[string]string) (*response, error) {
+	request, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
 	}
 
-	response, err := client.Do(request)
+	if len(body) != 0 {
+		request.Header.Set("Content-Type", contentType)
+	}
+	return c.send(request, headers)
+}
+
+func (c *Client) postJSON(ctx context.Context, url string, payload any, headers map[string]string) (*response, error) {
+	requestSchema, err := json.Marshal(payload)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
+	}
+
+	return c.sendRaw(ctx, http.MethodPost, url, requestSchema, "application/json", headers)
+}
+
+func (c *Client) patch(ctx context.Context, url string, payload any, headers map[string]string) (*response, error) {
+	var requestSchema []byte
+	if payload != nil {
+		var err error
+		requestSchema, err = json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	defer response.Body.Close()
-	responseBody, err := io.ReadAll(response.Body)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, bytes.NewBuffer(requestSchema))
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	return responseBody, response.StatusCode, nil
+	request.Header.Set("Content-Type", "application/json")
+	return c.send(request, headers)
 }
 
-func post(url string, payload any, headers map[string]string, parts map[string]io.Reader) (body []byte, status int, err error) {
+func (c *Client) post(ctx context.Context, url string, payload any, headers map[string]string, parts map[string]io.Reader) (*response, error) {
 	data, err := json.Marshal(payload)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
 	requestBody := &bytes.Buffer{}
@@ -86,58 +227,60 @@ func post(url string, payload any, headers map[string]string, parts map[string]i
 
 	w, err := writer.CreateFormField("data")
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
 	w.Write(data)
 
-	for key, reader := range parts {
+	// Write the parts in a consistent order, so the same request always has the same body
+	keys := make([]string, 0, len(parts))
+	for key := range parts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		reader := parts[key]
 		var fileWriter io.Writer
 		if x, ok := reader.(io.Closer); ok {
 			defer x.Close()
 		}
-		if x, ok := reader.(*os.File); ok {
+		if x, ok := reader.(interface{ Name() string }); ok {
 			fileWriter, err = writer.CreateFormFile(key, x.Name())
 			if err != nil {
-				return nil, 0, err
+				return nil, err
 			}
 		} else {
 			fileWriter, err = writer.CreateFormField(key)
 			if err != nil {
-				return nil, 0, err
+				return nil, err
 			}
 		}
 
 		if _, err = io.Copy(fileWriter, reader); err != nil {
-			return nil, 0, err
+			return nil, err
 		}
 	}
 
 	writer.Close()
 
-	client := &http.Client{}
-	request, err := http.NewRequest(http.MethodPost, url, requestBody)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, requestBody)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	for key, value := range headers {
-		request.Header.Set(key, value)
-	}
-
-	response, err := client.Do(request)
-	if err != nil {
-		return nil, 0, err
-	}
+	return c.send(request, headers)
+}
 
-	defer response.Body.Close()
-	responseBody, err := io.ReadAll(response.Body)
-	if err != nil {
-		return nil, 0, err
-	}
+// A reader which is uploaded as a file with the given name, like an *os.File
+type namedReader struct {
+	io.Reader
+	name string
+}
 
-	return responseBody, response.StatusCode, nil
+func (r namedReader) Name() string {
+	return r.name
 }
 
 func toMap[T any](object T) (map[string]any, error) {
@@ -1,25 +1,251 @@
 package gorinth
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	"io"
 )
 
 // Gets the version associated with the given ID
 func GetVersion(versionId string, auth string) (*Version, error) {
-	url := fmt.Sprintf("https://api.modrinth.com/v2/version/%s", versionId)
-	result, statusCode, err := get(url, authHeader(auth))
+	return DefaultClient.GetVersion(versionId, auth)
+}
+
+// Gets the version associated with the given ID, cancelling the request if the context is done
+func GetVersionContext(ctx context.Context, versionId string, auth string) (*Version, error) {
+	return DefaultClient.GetVersionContext(ctx, versionId, auth)
+}
+
+// Gets the version associated with the given ID
+func (c *Client) GetVersion(versionId string, auth string) (*Version, error) {
+	return c.GetVersionContext(context.Background(), versionId, auth)
+}
+
+// Gets the version associated with the given ID, cancelling the request if the context is done
+func (c *Client) GetVersionContext(ctx context.Context, versionId string, auth string) (*Version, error) {
+	url := c.endpoint("version", versionId)
+	response, err := c.get(ctx, url, c.authHeader(auth))
 	if err != nil {
 		return nil, err
 	}
 
-	if statusCode == 404 {
-		return nil, makeError("Version %q wasn't found or no authorization to see this project", versionId)
+	if response.status == 404 {
+		return nil, response.error("Version %q wasn't found or no authorization to see this project", versionId)
+	}
+
+	if response.status != 200 {
+		return nil, response.error("Unexpected status code when fetching version %q", versionId)
 	}
 
 	version := Version{}
 
-	json.Unmarshal(result, &version)
+	err = json.Unmarshal(response.body, &version)
+	if err != nil {
+		return nil, err
+	}
+	version.attach(c)
 
 	return &version, nil
 }
+
+// Associates the version and its dependencies with the client that fetched them
+func (version *Version) attach(c *Client) {
+	version.client = c
+	for i := range version.Dependencies {
+		version.Dependencies[i].client = c
+	}
+}
+
+// Modifies the version on Modrinth, sending exactly the fields which the patch sets or clears,
+// then fetches the version again so it reflects the changes
+func (version *Version) Modify(patch *VersionPatch, auth string) error {
+	return version.ModifyContext(context.Background(), patch, auth)
+}
+
+// Modifies the version on Modrinth, sending exactly the fields which the patch sets or clears,
+// then fetches the version again so it reflects the changes, cancelling the requests if the context is done
+func (version *Version) ModifyContext(ctx context.Context, patch *VersionPatch, auth string) error {
+	if patch.Empty() {
+		return nil
+	}
+
+	c := version.client.orDefault()
+	response, err := c.patch(ctx, c.endpoint("version", version.Id), patch.fields, c.authHeader(auth))
+	if err != nil {
+		return err
+	}
+
+	switch response.status {
+	case 204:
+	case 400:
+		return response.error("Invalid request when attempting to modify version %q", version.VersionNumber)
+	case 401:
+		return response.error("No authorisation to modify version %q", version.VersionNumber)
+	case 404:
+		return response.error("Version %q wasn't found or no authorization to see this version", version.VersionNumber)
+	default:
+		return response.error("Unexpected status code when modifying version %q", version.VersionNumber)
+	}
+
+	updated, err := c.GetVersionContext(ctx, version.Id, auth)
+	if err != nil {
+		return err
+	}
+
+	*version = *updated
+	return nil
+}
+
+// Deletes the version from Modrinth. Refuses to delete versions which other listed or archived versions
+// of the project depend on, returning ErrSafetyCheck, unless the Force option is given
+func (version *Version) Delete(auth string, opts ...Option) error {
+	return version.DeleteContext(context.Background(), auth, opts...)
+}
+
+// Deletes the version from Modrinth, cancelling the requests if the context is done.
+// Refuses to delete versions which other listed or archived versions of the project depend on,
+// returning ErrSafetyCheck, unless the Force option is given
+func (version *Version) DeleteContext(ctx context.Context, auth string, opts ...Option) error {
+	options := collectOptions(opts)
+	c := version.client.orDefault()
+
+	if !options.force {
+		dependents, err := version.dependents(ctx, auth)
+		if err != nil {
+			return err
+		}
+
+		if len(dependents) != 0 {
+			c.logger().Warn("Version is depended on by other versions of its project",
+				"version", version.VersionNumber,
+				"dependents", dependents,
+			)
+			return kindError{
+				gorinthError: makeError("Refusing to delete version %q, which versions %v depend on", version.VersionNumber, dependents),
+				kind:         ErrSafetyCheck,
+			}
+		}
+	}
+
+	response, err := c.delete(ctx, c.endpoint("version", version.Id), c.authHeader(auth))
+	if err != nil {
+		return err
+	}
+
+	if response.status == 204 {
+		return nil
+	}
+
+	if response.status == 404 {
+		return response.error("Version %q wasn't found or no authorization to see this version", version.VersionNumber)
+	}
+
+	if response.status == 401 {
+		return response.error("No authorisation to delete version %q", version.VersionNumber)
+	}
+
+	return response.error("Unexpected status code when deleting version %q", version.VersionNumber)
+}
+
+// Returns the version numbers of the other public versions of the project which depend on the version
+func (version *Version) dependents(ctx context.Context, auth string) ([]string, error) {
+	project, err := version.client.orDefault().GetProjectContext(ctx, version.ProjectId, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := project.GetVersionsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dependents := []string{}
+	for _, other := range versions {
+		if other.Id == version.Id || (other.Status != VersionListed && other.Status != VersionArchived) {
+			continue
+		}
+
+		for _, dependency := range other.Dependencies {
+			if dependency.VersionId != nil && *dependency.VersionId == version.Id {
+				dependents = append(dependents, other.VersionNumber)
+				break
+			}
+		}
+	}
+	return dependents, nil
+}
+
+// Uploads the files and adds them to the version, such as a resource pack which accompanies a datapack,
+// then fetches the version again so it includes the new files
+func (version *Version) AddFiles(files []FileUpload, auth string) error {
+	return version.AddFilesContext(context.Background(), files, auth)
+}
+
+// Uploads the files and adds them to the version, such as a resource pack which accompanies a datapack,
+// then fetches the version again so it includes the new files, cancelling the requests if the context is done
+func (version *Version) AddFilesContext(ctx context.Context, files []FileUpload, auth string) error {
+	parts, fileTypes, err := uploadParts(files)
+	if err != nil {
+		return err
+	}
+
+	c := version.client.orDefault()
+	payload := map[string]any{"file_types": fileTypes}
+	response, err := c.post(ctx, c.endpoint("version", version.Id, "file"), payload, c.authHeader(auth), parts)
+	if err != nil {
+		return err
+	}
+
+	switch response.status {
+	case 204:
+	case 400:
+		return response.error("Invalid request when attempting to add files to version %q", version.VersionNumber)
+	case 401:
+		return response.error("No authorisation to add files to version %q", version.VersionNumber)
+	case 404:
+		return response.error("Version %q wasn't found or no authorization to see this version", version.VersionNumber)
+	default:
+		return response.error("Unexpected status code when adding files to version %q", version.VersionNumber)
+	}
+
+	updated, err := c.GetVersionContext(ctx, version.Id, auth)
+	if err != nil {
+		return err
+	}
+
+	*version = *updated
+	return nil
+}
+
+// Returns the multipart parts for uploading the files, each named after its file,
+// and the types of the files which have one
+func uploadParts(files []FileUpload) (map[string]io.Reader, map[string]FileType, error) {
+	if len(files) == 0 {
+		return nil, nil, makeValidationError("At least one file must be uploaded")
+	}
+
+	parts := map[string]io.Reader{}
+	fileTypes := map[string]FileType{}
+	for _, file := range files {
+		if file.Name == "" {
+			return nil, nil, makeValidationError("Cannot upload a file without a name")
+		}
+		if file.Data == nil {
+			return nil, nil, makeValidationError("Cannot upload file %q without any data", file.Name)
+		}
+		if _, ok := parts[file.Name]; ok {
+			return nil, nil, makeValidationError("Cannot upload more than one file named %q", file.Name)
+		}
+
+		if file.FileType != nil {
+			if *file.FileType != FileRequiredRP && *file.FileType != FileOptionalRP {
+				return nil, nil, makeValidationError("File %q has unknown file type %q", file.Name, *file.FileType)
+			}
+			fileTypes[file.Name] = *file.FileType
+		}
+
+		parts[file.Name] = namedReader{Reader: file.Data, name: file.Name}
+	}
+
+	return parts, fileTypes, nil
+}